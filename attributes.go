@@ -0,0 +1,92 @@
+package gotel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// attributesFromMap converts a map of arbitrary values into attributes, sorted
+// by key so the result is deterministic.
+func attributesFromMap(values map[string]any) []attribute.KeyValue {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, attributeFromValue(key, values[key]))
+	}
+	return attrs
+}
+
+// attributeFromValue converts value into the attribute type matching its Go
+// type. Values without a matching type are formatted as strings.
+func attributeFromValue(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int64(key, int64(v))
+	case int16:
+		return attribute.Int64(key, int64(v))
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint8:
+		return attribute.Int64(key, int64(v))
+	case uint16:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case uint:
+		return uintAttribute(key, uint64(v))
+	case uint64:
+		return uintAttribute(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case time.Duration:
+		return attribute.String(key, v.String())
+	case time.Time:
+		return attribute.String(key, v.Format(time.RFC3339Nano))
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	case nil:
+		return attribute.String(key, "<nil>")
+	default:
+		return attribute.String(key, fmt.Sprintf("%v", v))
+	}
+}
+
+// uintAttribute keeps unsigned values that overflow an int64 as strings
+// rather than wrapping them around.
+func uintAttribute(key string, v uint64) attribute.KeyValue {
+	if v > math.MaxInt64 {
+		return attribute.String(key, fmt.Sprintf("%d", v))
+	}
+	return attribute.Int64(key, int64(v))
+}
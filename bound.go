@@ -0,0 +1,460 @@
+package gotel
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// boundOptions holds the measurement options of a bound handle. The attribute
+// set counts towards the instrument's cardinality limit once, at bind time,
+// unless it overflowed or the limit is reset every collection. Such handles
+// check the set on every measurement, so each measurement recorded into the
+// overflow series is counted, and a set can be admitted once room frees up.
+type boundOptions[O any] struct {
+	set      attribute.Set
+	limiter  *cardinalityLimiter
+	options  []O
+	overflow []O
+}
+
+func newBoundOptions[O any](
+	limiter *cardinalityLimiter, attrs []attribute.KeyValue, option func(attribute.Set) O,
+) boundOptions[O] {
+	set := attribute.NewSet(attrs...)
+	if limiter != nil && !limiter.delta && limiter.admit(set) {
+		limiter = nil
+	}
+
+	b := boundOptions[O]{set: set, limiter: limiter, options: []O{option(set)}}
+	if limiter != nil {
+		b.overflow = []O{option(overflowAttrs)}
+	}
+	return b
+}
+
+// get returns the options to record a measurement with.
+func (b *boundOptions[O]) get(ctx context.Context) []O {
+	if b.limiter == nil || b.limiter.admitted(ctx, b.set) {
+		return b.options
+	}
+	return b.overflow
+}
+
+func addOption(set attribute.Set) metric.AddOption       { return metric.WithAttributeSet(set) }
+func recordOption(set attribute.Set) metric.RecordOption { return metric.WithAttributeSet(set) }
+
+// BoundCounter is an Int64Counter bound to a fixed attribute set, so hot paths
+// can record without building the set on every call.
+//
+// Binding a nil instrument returns a nil handle, which discards measurements.
+// This lets callers bind optional instruments without checking them first.
+type BoundCounter struct {
+	counter metric.Int64Counter
+	options boundOptions[metric.AddOption]
+}
+
+// BindCounter binds counter to the given attributes. When counter was created
+// by a MetricRegistry, a set that fits under its cardinality limit is checked
+// once, at bind time. A set that does not fit records into the overflow series,
+// and every such measurement is counted in metric_cardinality_overflow_total.
+func BindCounter(counter metric.Int64Counter, attrs ...attribute.KeyValue) *BoundCounter {
+	if counter == nil {
+		return nil
+	}
+
+	if wrapped, ok := counter.(*Counter); ok {
+		counter = wrapped.Int64Counter
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := counter.(*limitedInt64Counter); ok {
+		counter, limiter = limited.Int64Counter, limited.limiter
+	}
+	return &BoundCounter{counter: counter, options: newBoundOptions(limiter, attrs, addOption)}
+}
+
+// Add records a change to the counter.
+func (b *BoundCounter) Add(ctx context.Context, incr int64) {
+	if b == nil {
+		return
+	}
+	b.counter.Add(ctx, incr, b.options.get(ctx)...)
+}
+
+// BoundFloatCounter is a Float64Counter bound to a fixed attribute set.
+type BoundFloatCounter struct {
+	counter metric.Float64Counter
+	options boundOptions[metric.AddOption]
+}
+
+// BindFloatCounter binds counter to the given attributes.
+func BindFloatCounter(counter metric.Float64Counter, attrs ...attribute.KeyValue) *BoundFloatCounter {
+	if counter == nil {
+		return nil
+	}
+
+	if wrapped, ok := counter.(*FloatCounter); ok {
+		counter = wrapped.Float64Counter
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := counter.(*limitedFloat64Counter); ok {
+		counter, limiter = limited.Float64Counter, limited.limiter
+	}
+	return &BoundFloatCounter{counter: counter, options: newBoundOptions(limiter, attrs, addOption)}
+}
+
+// Add records a change to the counter.
+func (b *BoundFloatCounter) Add(ctx context.Context, incr float64) {
+	if b == nil {
+		return
+	}
+	b.counter.Add(ctx, incr, b.options.get(ctx)...)
+}
+
+// BoundUpDownCounter is an Int64UpDownCounter bound to a fixed attribute set.
+type BoundUpDownCounter struct {
+	counter metric.Int64UpDownCounter
+	options boundOptions[metric.AddOption]
+}
+
+// BindUpDownCounter binds counter to the given attributes.
+func BindUpDownCounter(counter metric.Int64UpDownCounter, attrs ...attribute.KeyValue) *BoundUpDownCounter {
+	if counter == nil {
+		return nil
+	}
+
+	if wrapped, ok := counter.(*UpDownCounter); ok {
+		counter = wrapped.Int64UpDownCounter
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := counter.(*limitedInt64UpDownCounter); ok {
+		counter, limiter = limited.Int64UpDownCounter, limited.limiter
+	}
+	return &BoundUpDownCounter{counter: counter, options: newBoundOptions(limiter, attrs, addOption)}
+}
+
+// Add records a change to the counter.
+func (b *BoundUpDownCounter) Add(ctx context.Context, incr int64) {
+	if b == nil {
+		return
+	}
+	b.counter.Add(ctx, incr, b.options.get(ctx)...)
+}
+
+// BoundFloatUpDownCounter is a Float64UpDownCounter bound to a fixed attribute set.
+type BoundFloatUpDownCounter struct {
+	counter metric.Float64UpDownCounter
+	options boundOptions[metric.AddOption]
+}
+
+// BindFloatUpDownCounter binds counter to the given attributes.
+func BindFloatUpDownCounter(counter metric.Float64UpDownCounter, attrs ...attribute.KeyValue) *BoundFloatUpDownCounter {
+	if counter == nil {
+		return nil
+	}
+
+	if wrapped, ok := counter.(*FloatUpDownCounter); ok {
+		counter = wrapped.Float64UpDownCounter
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := counter.(*limitedFloat64UpDownCounter); ok {
+		counter, limiter = limited.Float64UpDownCounter, limited.limiter
+	}
+	return &BoundFloatUpDownCounter{counter: counter, options: newBoundOptions(limiter, attrs, addOption)}
+}
+
+// Add records a change to the counter.
+func (b *BoundFloatUpDownCounter) Add(ctx context.Context, incr float64) {
+	if b == nil {
+		return
+	}
+	b.counter.Add(ctx, incr, b.options.get(ctx)...)
+}
+
+// BoundHistogram is a Float64Histogram bound to a fixed attribute set.
+type BoundHistogram struct {
+	histogram metric.Float64Histogram
+	options   boundOptions[metric.RecordOption]
+}
+
+// BindHistogram binds histogram to the given attributes.
+func BindHistogram(histogram metric.Float64Histogram, attrs ...attribute.KeyValue) *BoundHistogram {
+	if histogram == nil {
+		return nil
+	}
+
+	if wrapped, ok := histogram.(*Histogram); ok {
+		histogram = wrapped.Float64Histogram
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := histogram.(*limitedFloat64Histogram); ok {
+		histogram, limiter = limited.Float64Histogram, limited.limiter
+	}
+	return &BoundHistogram{histogram: histogram, options: newBoundOptions(limiter, attrs, recordOption)}
+}
+
+// Record adds a value to the histogram.
+func (b *BoundHistogram) Record(ctx context.Context, value float64) {
+	if b == nil {
+		return
+	}
+	b.histogram.Record(ctx, value, b.options.get(ctx)...)
+}
+
+// BoundIntHistogram is an Int64Histogram bound to a fixed attribute set.
+type BoundIntHistogram struct {
+	histogram metric.Int64Histogram
+	options   boundOptions[metric.RecordOption]
+}
+
+// BindIntHistogram binds histogram to the given attributes.
+func BindIntHistogram(histogram metric.Int64Histogram, attrs ...attribute.KeyValue) *BoundIntHistogram {
+	if histogram == nil {
+		return nil
+	}
+
+	if wrapped, ok := histogram.(*IntHistogram); ok {
+		histogram = wrapped.Int64Histogram
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := histogram.(*limitedInt64Histogram); ok {
+		histogram, limiter = limited.Int64Histogram, limited.limiter
+	}
+	return &BoundIntHistogram{histogram: histogram, options: newBoundOptions(limiter, attrs, recordOption)}
+}
+
+// Record adds a value to the histogram.
+func (b *BoundIntHistogram) Record(ctx context.Context, value int64) {
+	if b == nil {
+		return
+	}
+	b.histogram.Record(ctx, value, b.options.get(ctx)...)
+}
+
+// BoundSyncGauge is an Int64Gauge bound to a fixed attribute set.
+type BoundSyncGauge struct {
+	gauge   metric.Int64Gauge
+	options boundOptions[metric.RecordOption]
+}
+
+// BindSyncGauge binds gauge to the given attributes.
+func BindSyncGauge(gauge metric.Int64Gauge, attrs ...attribute.KeyValue) *BoundSyncGauge {
+	if gauge == nil {
+		return nil
+	}
+
+	if wrapped, ok := gauge.(*SyncGauge); ok {
+		gauge = wrapped.Int64Gauge
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := gauge.(*limitedInt64Gauge); ok {
+		gauge, limiter = limited.Int64Gauge, limited.limiter
+	}
+	return &BoundSyncGauge{gauge: gauge, options: newBoundOptions(limiter, attrs, recordOption)}
+}
+
+// Record sets the current value of the gauge.
+func (b *BoundSyncGauge) Record(ctx context.Context, value int64) {
+	if b == nil {
+		return
+	}
+	b.gauge.Record(ctx, value, b.options.get(ctx)...)
+}
+
+// BoundFloatSyncGauge is a Float64Gauge bound to a fixed attribute set.
+type BoundFloatSyncGauge struct {
+	gauge   metric.Float64Gauge
+	options boundOptions[metric.RecordOption]
+}
+
+// BindFloatSyncGauge binds gauge to the given attributes.
+func BindFloatSyncGauge(gauge metric.Float64Gauge, attrs ...attribute.KeyValue) *BoundFloatSyncGauge {
+	if gauge == nil {
+		return nil
+	}
+
+	if wrapped, ok := gauge.(*FloatSyncGauge); ok {
+		gauge = wrapped.Float64Gauge
+	}
+
+	var limiter *cardinalityLimiter
+	if limited, ok := gauge.(*limitedFloat64Gauge); ok {
+		gauge, limiter = limited.Float64Gauge, limited.limiter
+	}
+	return &BoundFloatSyncGauge{gauge: gauge, options: newBoundOptions(limiter, attrs, recordOption)}
+}
+
+// Record sets the current value of the gauge.
+func (b *BoundFloatSyncGauge) Record(ctx context.Context, value float64) {
+	if b == nil {
+		return
+	}
+	b.gauge.Record(ctx, value, b.options.get(ctx)...)
+}
+
+// Counter is an Int64Counter created by a MetricRegistry. Bind returns a
+// handle with a precomputed attribute set for hot paths.
+type Counter struct {
+	metric.Int64Counter
+}
+
+// Bind binds the counter to the given attributes, like BindCounter.
+func (c *Counter) Bind(attrs ...attribute.KeyValue) *BoundCounter {
+	if c == nil {
+		return nil
+	}
+	return BindCounter(c.Int64Counter, attrs...)
+}
+
+// FloatCounter is a Float64Counter created by a MetricRegistry.
+type FloatCounter struct {
+	metric.Float64Counter
+}
+
+// Bind binds the counter to the given attributes, like BindFloatCounter.
+func (c *FloatCounter) Bind(attrs ...attribute.KeyValue) *BoundFloatCounter {
+	if c == nil {
+		return nil
+	}
+	return BindFloatCounter(c.Float64Counter, attrs...)
+}
+
+// UpDownCounter is an Int64UpDownCounter created by a MetricRegistry.
+type UpDownCounter struct {
+	metric.Int64UpDownCounter
+}
+
+// Bind binds the counter to the given attributes, like BindUpDownCounter.
+func (c *UpDownCounter) Bind(attrs ...attribute.KeyValue) *BoundUpDownCounter {
+	if c == nil {
+		return nil
+	}
+	return BindUpDownCounter(c.Int64UpDownCounter, attrs...)
+}
+
+// FloatUpDownCounter is a Float64UpDownCounter created by a MetricRegistry.
+type FloatUpDownCounter struct {
+	metric.Float64UpDownCounter
+}
+
+// Bind binds the counter to the given attributes, like BindFloatUpDownCounter.
+func (c *FloatUpDownCounter) Bind(attrs ...attribute.KeyValue) *BoundFloatUpDownCounter {
+	if c == nil {
+		return nil
+	}
+	return BindFloatUpDownCounter(c.Float64UpDownCounter, attrs...)
+}
+
+// Histogram is a Float64Histogram created by a MetricRegistry.
+type Histogram struct {
+	metric.Float64Histogram
+}
+
+// Bind binds the histogram to the given attributes, like BindHistogram.
+func (h *Histogram) Bind(attrs ...attribute.KeyValue) *BoundHistogram {
+	if h == nil {
+		return nil
+	}
+	return BindHistogram(h.Float64Histogram, attrs...)
+}
+
+// IntHistogram is an Int64Histogram created by a MetricRegistry.
+type IntHistogram struct {
+	metric.Int64Histogram
+}
+
+// Bind binds the histogram to the given attributes, like BindIntHistogram.
+func (h *IntHistogram) Bind(attrs ...attribute.KeyValue) *BoundIntHistogram {
+	if h == nil {
+		return nil
+	}
+	return BindIntHistogram(h.Int64Histogram, attrs...)
+}
+
+// SyncGauge is an Int64Gauge created by a MetricRegistry. Observable gauges
+// have no bound form, since they record through the observer passed to their
+// callback.
+type SyncGauge struct {
+	metric.Int64Gauge
+}
+
+// Bind binds the gauge to the given attributes, like BindSyncGauge.
+func (g *SyncGauge) Bind(attrs ...attribute.KeyValue) *BoundSyncGauge {
+	if g == nil {
+		return nil
+	}
+	return BindSyncGauge(g.Int64Gauge, attrs...)
+}
+
+// FloatSyncGauge is a Float64Gauge created by a MetricRegistry.
+type FloatSyncGauge struct {
+	metric.Float64Gauge
+}
+
+// Bind binds the gauge to the given attributes, like BindFloatSyncGauge.
+func (g *FloatSyncGauge) Bind(attrs ...attribute.KeyValue) *BoundFloatSyncGauge {
+	if g == nil {
+		return nil
+	}
+	return BindFloatSyncGauge(g.Float64Gauge, attrs...)
+}
+
+// maxBoundCacheSize caps the number of bound handles kept by a boundCache.
+const maxBoundCacheSize = 1024
+
+// boundCache memoizes bound instrument handles by key. Once full, keys that
+// are not cached share a single handle built by overflow, typically bound to
+// the overflow attribute set, so unbounded keys neither grow memory nor pay
+// for building a handle on every call.
+type boundCache[K comparable, V any] struct {
+	mu          sync.RWMutex
+	entries     map[K]V
+	overflow    func() V
+	overflowed  bool
+	overflowVal V
+}
+
+func newBoundCache[K comparable, V any](overflow func() V) *boundCache[K, V] {
+	return &boundCache[K, V]{entries: make(map[K]V), overflow: overflow}
+}
+
+// get returns the cached value for key, building it with create on a miss.
+func (c *boundCache[K, V]) get(key K, create func() V) V {
+	c.mu.RLock()
+	value, ok := c.entries[key]
+	if !ok && c.overflowed {
+		value, ok = c.overflowVal, true
+	}
+	c.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, ok := c.entries[key]; ok {
+		return value
+	}
+
+	if len(c.entries) >= maxBoundCacheSize {
+		if !c.overflowed {
+			c.overflowVal = c.overflow()
+			c.overflowed = true
+		}
+		return c.overflowVal
+	}
+
+	value = create()
+	c.entries[key] = value
+	return value
+}
@@ -0,0 +1,268 @@
+package gotel_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Bound instruments", func() {
+	var (
+		ctx    context.Context
+		reader *sdkmetric.ManualReader
+		meter  metric.Meter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+		meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("bound-test")
+	})
+
+	It("should record with the bound attributes", func() {
+		registry := gotel.NewMetricRegistry(meter, "app")
+		counter, err := registry.Counter("jobs_total", "Jobs")
+		Expect(err).NotTo(HaveOccurred())
+		histogram, err := registry.Histogram("job_seconds", "Job duration")
+		Expect(err).NotTo(HaveOccurred())
+
+		attrs := []attribute.KeyValue{attribute.String("queue", "emails")}
+		jobs := counter.Bind(attrs...)
+		durations := histogram.Bind(attrs...)
+
+		jobs.Add(ctx, 2)
+		jobs.Add(ctx, 3)
+		durations.Record(ctx, 0.5)
+
+		metrics := collectMetrics(ctx, reader)
+		point := metrics["app_jobs_total"].Data.(metricdata.Sum[int64]).DataPoints[0]
+		Expect(point.Value).To(Equal(int64(5)))
+		want := attribute.NewSet(attrs...)
+		Expect(point.Attributes.Equals(&want)).To(BeTrue())
+
+		hist := metrics["app_job_seconds"].Data.(metricdata.Histogram[float64]).DataPoints[0]
+		Expect(hist.Sum).To(Equal(0.5))
+	})
+
+	It("should count a bound set against the cardinality limit once", func() {
+		registry := gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(1))
+		counter, err := registry.Counter("requests_total", "Requests")
+		Expect(err).NotTo(HaveOccurred())
+
+		first := gotel.BindCounter(counter, attribute.String("route", "/a"))
+		second := gotel.BindCounter(counter, attribute.String("route", "/b"))
+		first.Add(ctx, 1)
+		first.Add(ctx, 1)
+		second.Add(ctx, 1)
+
+		points := collectMetrics(ctx, reader)["requests_total"].Data.(metricdata.Sum[int64]).DataPoints
+		Expect(points).To(HaveLen(2))
+
+		values := map[bool]int64{}
+		for _, p := range points {
+			_, overflow := p.Attributes.Value("otel.metric.overflow")
+			values[overflow] = p.Value
+		}
+		Expect(values).To(Equal(map[bool]int64{false: 2, true: 1}))
+	})
+
+	It("should count every measurement a bound handle records into the overflow series", func() {
+		registry := gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(1))
+		counter, err := registry.Counter("requests_total", "Requests")
+		Expect(err).NotTo(HaveOccurred())
+
+		counter.Bind(attribute.String("route", "/a")).Add(ctx, 1)
+		overflowed := counter.Bind(attribute.String("route", "/b"))
+		for range 3 {
+			overflowed.Add(ctx, 1)
+		}
+
+		overflow := collectMetrics(ctx, reader)["metric_cardinality_overflow_total"].Data.(metricdata.Sum[int64])
+		Expect(overflow.DataPoints).To(HaveLen(1))
+		Expect(overflow.DataPoints[0].Value).To(Equal(int64(3)))
+	})
+
+	It("should admit a bound set once a delta collection frees up the limit", func() {
+		delta := func(sdkmetric.InstrumentKind) metricdata.Temporality { return metricdata.DeltaTemporality }
+		reader = sdkmetric.NewManualReader(sdkmetric.WithTemporalitySelector(delta))
+		meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("bound-test")
+
+		registry := gotel.NewMetricRegistry(meter, "",
+			gotel.WithCardinalityLimit(1),
+			gotel.WithCardinalityReset(delta),
+		)
+		counter, err := registry.Counter("requests_total", "Requests")
+		Expect(err).NotTo(HaveOccurred())
+
+		first := counter.Bind(attribute.String("route", "/a"))
+		second := counter.Bind(attribute.String("route", "/b"))
+		first.Add(ctx, 1)
+		collectMetrics(ctx, reader)
+
+		second.Add(ctx, 1)
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics).NotTo(HaveKey("metric_cardinality_overflow_total"))
+
+		points := metrics["requests_total"].Data.(metricdata.Sum[int64]).DataPoints
+		Expect(points).To(HaveLen(1))
+		route, _ := points[0].Attributes.Value("route")
+		Expect(route.AsString()).To(Equal("/b"))
+	})
+
+	It("should bind float up/down counters and sync gauges", func() {
+		registry := gotel.NewMetricRegistry(meter, "")
+		inFlight, err := registry.FloatUpDownCounter("queue_bytes", "Queued bytes")
+		Expect(err).NotTo(HaveOccurred())
+		workers, err := registry.SyncGauge("workers", "Workers")
+		Expect(err).NotTo(HaveOccurred())
+		load, err := registry.FloatSyncGauge("load", "Load")
+		Expect(err).NotTo(HaveOccurred())
+
+		attrs := []attribute.KeyValue{attribute.String("queue", "emails")}
+		queued := inFlight.Bind(attrs...)
+		queued.Add(ctx, 2.5)
+		queued.Add(ctx, -1)
+		workers.Bind(attrs...).Record(ctx, 4)
+		load.Bind(attrs...).Record(ctx, 0.75)
+
+		var nilGauge *gotel.SyncGauge
+		nilGauge.Bind(attrs...).Record(ctx, 1)
+
+		metrics := collectMetrics(ctx, reader)
+		want := attribute.NewSet(attrs...)
+
+		sum := metrics["queue_bytes"].Data.(metricdata.Sum[float64]).DataPoints[0]
+		Expect(sum.Value).To(Equal(1.5))
+		Expect(sum.Attributes.Equals(&want)).To(BeTrue())
+
+		gauge := metrics["workers"].Data.(metricdata.Gauge[int64]).DataPoints[0]
+		Expect(gauge.Value).To(Equal(int64(4)))
+		Expect(gauge.Attributes.Equals(&want)).To(BeTrue())
+
+		Expect(metrics["load"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value).To(Equal(0.75))
+	})
+
+	It("should collapse routes beyond the middleware cache into the overflow series", func() {
+		metrics, err := gotel.NewCommonMetrics(gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(0)))
+		Expect(err).NotTo(HaveOccurred())
+
+		tracer := gotel.NewTracer(noop.NewTracerProvider().Tracer("bound-test"))
+		handler := gotel.NewHTTPMiddleware("bound-test", tracer, metrics).Handler(
+			http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		)
+		for i := range 1100 {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%d", i), nil))
+		}
+
+		points := collectMetrics(ctx, reader)["http_requests_total"].Data.(metricdata.Sum[int64]).DataPoints
+		Expect(len(points)).To(BeNumerically("<=", 1025))
+
+		var total, overflow int64
+		for _, p := range points {
+			total += p.Value
+			if _, ok := p.Attributes.Value("otel.metric.overflow"); ok {
+				overflow = p.Value
+			}
+		}
+		Expect(total).To(Equal(int64(1100)))
+		Expect(overflow).To(BeNumerically(">=", 1100-1024))
+	})
+})
+
+func benchmarkCounter(b *testing.B) metric.Int64Counter {
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader())).Meter("bench")
+	counter, err := gotel.NewMetricRegistry(meter, "bench").Counter("requests_total", "Requests")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return counter
+}
+
+func BenchmarkCounterAddWithAttributes(b *testing.B) {
+	ctx := context.Background()
+	counter := benchmarkCounter(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		counter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("method", "GET"),
+			attribute.String("route", "/users"),
+		))
+	}
+}
+
+func BenchmarkBoundCounterAdd(b *testing.B) {
+	ctx := context.Background()
+	bound := gotel.BindCounter(benchmarkCounter(b),
+		attribute.String("method", "GET"),
+		attribute.String("route", "/users"),
+	)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		bound.Add(ctx, 1)
+	}
+}
+
+// unboundMiddleware reproduces the middleware's recording before bound
+// instruments, building the attribute sets on every request.
+func unboundMiddleware(tracer *gotel.Tracer, metrics *gotel.CommonMetrics, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.StartSpan(r.Context(), r.Method+" "+r.URL.Path)
+		defer span.End()
+
+		attrs := []attribute.KeyValue{
+			attribute.String("method", r.Method),
+			attribute.String("route", r.URL.Path),
+		}
+		metrics.HTTPActiveRequests.Add(ctx, 1, metric.WithAttributes(attrs...))
+		defer metrics.HTTPActiveRequests.Add(ctx, -1, metric.WithAttributes(attrs...))
+
+		rec := httptest.NewRecorder()
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		duration := time.Since(start)
+
+		statusAttrs := append(attrs, attribute.String("status_code", strconv.Itoa(rec.Code)))
+		metrics.HTTPRequestsTotal.Add(ctx, 1, metric.WithAttributes(statusAttrs...))
+		metrics.HTTPRequestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+	})
+}
+
+func BenchmarkHTTPMiddleware(b *testing.B) {
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader())).Meter("bench")
+	metrics, err := gotel.NewCommonMetrics(gotel.NewMetricRegistry(meter, "bench"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	tracer := gotel.NewTracer(noop.NewTracerProvider().Tracer("bench"))
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	run := func(handler http.Handler) func(*testing.B) {
+		return func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}
+		}
+	}
+
+	b.Run("unbound", run(unboundMiddleware(tracer, metrics, next)))
+	b.Run("bound", run(gotel.NewHTTPMiddleware("bench", tracer, metrics).Handler(next)))
+}
@@ -0,0 +1,139 @@
+package gotel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CallbackRegistration is a handle to a callback registered through
+// MetricRegistry.RegisterCallback.
+type CallbackRegistration struct {
+	registry     *MetricRegistry
+	registration metric.Registration
+	instruments  []metric.Observable
+	limiters     map[metric.Observable]*cardinalityLimiter
+	mu           sync.Mutex
+	unregistered bool
+}
+
+// RegisterCallback registers a callback that observes several observable
+// instruments in a single pass, e.g. reading a connection pool's stats once
+// and reporting them through multiple instruments. The instruments are
+// usually created through the registry with a nil callback.
+//
+// The returned handle unregisters the callback and removes the instruments'
+// bookkeeping from the registry, so components that come and go do not leak
+// callbacks or registry entries.
+func (m *MetricRegistry) RegisterCallback(
+	callback metric.Callback, instruments ...metric.Observable,
+) (*CallbackRegistration, error) {
+	limiters := make(map[metric.Observable]*cardinalityLimiter, len(instruments))
+	for _, instrument := range instruments {
+		registered, metricName, ok := m.registeredInstrument(instrument)
+		if !ok {
+			continue
+		}
+		if limiter := m.newCardinalityLimiter(registered.name, metricName, registered.kind); limiter != nil {
+			limiters[instrument] = limiter
+		}
+	}
+
+	observe := callback
+	if len(limiters) > 0 {
+		observe = func(ctx context.Context, o metric.Observer) error {
+			return callback(ctx, &limitedObserver{Observer: o, ctx: ctx, limiters: limiters})
+		}
+	}
+
+	registration, err := m.meter.RegisterCallback(observe, instruments...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register callback: %w", err)
+	}
+
+	return &CallbackRegistration{
+		registry:     m,
+		registration: registration,
+		instruments:  instruments,
+		limiters:     limiters,
+	}, nil
+}
+
+// Unregister stops the callback from being invoked and removes the instruments
+// it observed from the registry. Requesting one of their names again creates a
+// fresh registry entry, which must be of the same instrument kind since the
+// SDK meter keeps the original instrument. Calling Unregister more than once is
+// a no-op.
+func (r *CallbackRegistration) Unregister() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.unregistered {
+		return nil
+	}
+
+	if err := r.registration.Unregister(); err != nil {
+		return fmt.Errorf("failed to unregister callback: %w", err)
+	}
+
+	r.unregistered = true
+	r.registry.forget(r.instruments...)
+	r.registry.releaseLimiters(r.limiters)
+	return nil
+}
+
+// forget removes the bookkeeping of the given instruments from the registry,
+// remembering only the kind each name was registered as.
+func (m *MetricRegistry) forget(instruments ...metric.Observable) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, instrument := range instruments {
+		for name, registered := range m.instruments {
+			if registered.instrument == instrument {
+				m.forgotten[name] = registered.kind
+				delete(m.instruments, name)
+			}
+		}
+	}
+}
+
+// registeredInstrument returns the registry entry of instrument and its
+// generated name.
+func (m *MetricRegistry) registeredInstrument(instrument metric.Observable) (registeredInstrument, string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for metricName, registered := range m.instruments {
+		if registered.instrument == instrument {
+			return registered, metricName, true
+		}
+	}
+	return registeredInstrument{}, "", false
+}
+
+// limitedObserver applies per-instrument cardinality limits to observations
+// made from a multi-instrument callback.
+type limitedObserver struct {
+	metric.Observer
+	ctx      context.Context
+	limiters map[metric.Observable]*cardinalityLimiter
+}
+
+func (o *limitedObserver) ObserveInt64(instrument metric.Int64Observable, value int64, options ...metric.ObserveOption) {
+	if limiter, ok := o.limiters[instrument]; ok {
+		attrs := metric.NewObserveConfig(options).Attributes()
+		options = []metric.ObserveOption{metric.WithAttributeSet(limiter.attributes(o.ctx, attrs))}
+	}
+	o.Observer.ObserveInt64(instrument, value, options...)
+}
+
+func (o *limitedObserver) ObserveFloat64(instrument metric.Float64Observable, value float64, options ...metric.ObserveOption) {
+	if limiter, ok := o.limiters[instrument]; ok {
+		attrs := metric.NewObserveConfig(options).Attributes()
+		options = []metric.ObserveOption{metric.WithAttributeSet(limiter.attributes(o.ctx, attrs))}
+	}
+	o.Observer.ObserveFloat64(instrument, value, options...)
+}
@@ -0,0 +1,103 @@
+package gotel
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the tracer name used by spans started without an
+// explicit Tracer.
+const instrumentationName = "github.com/iamBelugax/gotel"
+
+// callerInfo is the span name and code attributes derived from a call site.
+type callerInfo struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// callers caches callerInfo by program counter, since resolving a function
+// name is far more expensive than capturing the program counter.
+var callers sync.Map
+
+// WithCodeAttributes makes Tracer.Auto add code.function, code.filepath and
+// code.lineno attributes describing the call site.
+func WithCodeAttributes() TracerOption {
+	return func(t *Tracer) {
+		t.codeAttributes = true
+	}
+}
+
+// Auto starts a span named after the calling function, e.g.
+// "users.(*UserService).GetUser" becomes "users.UserService.GetUser".
+func (t *Tracer) Auto(ctx context.Context, opts ...trace.SpanStartOption) (context.Context, *Span) {
+	info := caller(2)
+	if t.codeAttributes {
+		// Clip opts so appending never writes into the caller's backing array.
+		opts = append(slices.Clip(opts), trace.WithAttributes(info.attrs...))
+	}
+	ctx, span := NewSpan(ctx, t.tracer, info.name, opts...)
+	span.classifier = t.classifier
+	return ctx, span
+}
+
+// Trace starts a span named after the calling function, with code.* attributes
+// describing the call site. The span is created by the tracer provider of the
+// span in ctx, or by the global tracer provider when ctx has no span.
+func Trace(ctx context.Context, opts ...trace.SpanStartOption) (context.Context, *Span) {
+	info := caller(2)
+
+	provider := otel.GetTracerProvider()
+	if parent := trace.SpanFromContext(ctx); parent.SpanContext().IsValid() {
+		provider = parent.TracerProvider()
+	}
+
+	opts = append(slices.Clip(opts), trace.WithAttributes(info.attrs...))
+	return NewSpan(ctx, provider.Tracer(instrumentationName), info.name, opts...)
+}
+
+// caller returns the callerInfo of the function skip frames up the stack, where
+// 0 is caller itself.
+func caller(skip int) *callerInfo {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return &callerInfo{name: "unknown"}
+	}
+
+	if info, ok := callers.Load(pcs[0]); ok {
+		return info.(*callerInfo)
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	info := &callerInfo{
+		name: spanNameFromFunction(frame.Function),
+		attrs: []attribute.KeyValue{
+			semconv.CodeFunction(frame.Function),
+			semconv.CodeFilepath(frame.File),
+			semconv.CodeLineNumber(frame.Line),
+		},
+	}
+
+	actual, _ := callers.LoadOrStore(pcs[0], info)
+	return actual.(*callerInfo)
+}
+
+// spanNameFromFunction turns a fully qualified function name such as
+// "github.com/acme/users.(*UserService).GetUser" into "users.UserService.GetUser".
+func spanNameFromFunction(function string) string {
+	if function == "" {
+		return "unknown"
+	}
+
+	if slash := strings.LastIndexByte(function, '/'); slash >= 0 {
+		function = function[slash+1:]
+	}
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(function)
+}
@@ -0,0 +1,112 @@
+package gotel_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/iamBelugax/gotel"
+)
+
+type orderService struct {
+	tracer *gotel.Tracer
+}
+
+func (s *orderService) Place(ctx context.Context) {
+	_, span := s.tracer.Auto(ctx)
+	span.End()
+}
+
+func (s orderService) Cancel(ctx context.Context) {
+	_, span := gotel.Trace(ctx)
+	span.End()
+}
+
+// spanAttrs returns the attributes of span keyed by name.
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+var _ = Describe("Automatic span naming", func() {
+	var (
+		ctx      context.Context
+		recorder *tracetest.SpanRecorder
+		provider *sdktrace.TracerProvider
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	})
+
+	It("should name spans after the calling method without code attributes by default", func() {
+		service := &orderService{tracer: gotel.NewTracer(provider.Tracer("caller-test"))}
+		service.Place(ctx)
+		service.Place(ctx)
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("gotel_test.orderService.Place"))
+		Expect(spans[1].Name()).To(Equal(spans[0].Name()))
+		Expect(spans[0].Attributes()).To(BeEmpty())
+	})
+
+	It("should add code attributes when configured", func() {
+		tracer := gotel.NewTracer(provider.Tracer("caller-test"), gotel.WithCodeAttributes())
+		(&orderService{tracer: tracer}).Place(ctx)
+
+		attrs := spanAttrs(recorder.Ended()[0])
+		Expect(attrs["code.function"].AsString()).To(HaveSuffix("gotel_test.(*orderService).Place"))
+		Expect(attrs["code.filepath"].AsString()).To(HaveSuffix("caller_test.go"))
+		Expect(attrs["code.lineno"].AsInt64()).To(BeNumerically(">", 0))
+	})
+
+	It("should not write into the spare capacity of the caller's options", func() {
+		tracer := gotel.NewTracer(provider.Tracer("caller-test"), gotel.WithCodeAttributes())
+		opts := []trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindServer), trace.WithSpanKind(trace.SpanKindClient)}
+
+		_, span := tracer.Auto(ctx, opts[:1]...)
+		span.End()
+		_, span = gotel.Trace(ctx, opts[:1]...)
+		span.End()
+
+		spare := trace.NewSpanStartConfig(opts[1])
+		Expect(spare.SpanKind()).To(Equal(trace.SpanKindClient))
+		Expect(spare.Attributes()).To(BeEmpty())
+	})
+
+	It("should start Trace spans from the provider of the parent span", func() {
+		parentCtx, parent := provider.Tracer("caller-test").Start(ctx, "parent")
+		orderService{}.Cancel(parentCtx)
+		parent.End()
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("gotel_test.orderService.Cancel"))
+		Expect(spans[0].Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(spanAttrs(spans[0])).To(HaveKey(attribute.Key("code.lineno")))
+	})
+})
+
+func BenchmarkTracerAuto(b *testing.B) {
+	ctx := context.Background()
+	tracer := gotel.NewTracer(noop.NewTracerProvider().Tracer("bench"))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_, span := tracer.Auto(ctx)
+		span.End()
+	}
+}
@@ -0,0 +1,339 @@
+package gotel
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+// DefaultCardinalityLimit is the number of distinct attribute sets an
+// instrument created through a MetricRegistry may record before additional
+// sets are collapsed into the overflow series.
+const DefaultCardinalityLimit = 2000
+
+// overflowAttrs is recorded in place of attribute sets that exceed the limit,
+// matching the overflow attribute used by the OpenTelemetry SDK.
+var overflowAttrs = attribute.NewSet(attribute.Bool("otel.metric.overflow", true))
+
+// cardinalityLimiter tracks the distinct attribute sets recorded by a single
+// instrument and redirects new sets to the overflow series once the limit is hit.
+// The sets are tracked for the registry's lifetime, unless the instrument is
+// exported with delta temporality and the registry was created
+// WithCardinalityReset, in which case they are forgotten at every collection.
+type cardinalityLimiter struct {
+	name       string
+	limit      int
+	registry   *MetricRegistry
+	mu         sync.Mutex
+	seen       map[attribute.Distinct]struct{}
+	overflowed bool
+	delta      bool
+}
+
+// newCardinalityLimiter returns nil when the instrument is not limited. name is
+// the name requested from the registry and metricName the generated one.
+func (m *MetricRegistry) newCardinalityLimiter(name, metricName string, kind instrumentKind) *cardinalityLimiter {
+	limit := m.cardinalityLimit
+	if override, ok := m.cardinalityLimits[name]; ok {
+		limit = override
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	l := &cardinalityLimiter{
+		name:     metricName,
+		limit:    limit,
+		registry: m,
+		seen:     make(map[attribute.Distinct]struct{}),
+	}
+
+	if m.temporality != nil && m.temporality(kind.sdkKind()) == metricdata.DeltaTemporality {
+		l.delta = true
+		m.limitersMu.Lock()
+		m.deltaLimiters = append(m.deltaLimiters, l)
+		m.limitersMu.Unlock()
+	}
+	return l
+}
+
+// reset forgets the tracked attribute sets, so the next collection cycle starts
+// with the full limit available.
+func (l *cardinalityLimiter) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.seen)
+}
+
+// releaseLimiters stops resetting the given limiters once their instruments
+// are no longer observed.
+func (m *MetricRegistry) releaseLimiters(limiters map[metric.Observable]*cardinalityLimiter) {
+	if len(limiters) == 0 {
+		return
+	}
+
+	m.limitersMu.Lock()
+	defer m.limitersMu.Unlock()
+
+	m.deltaLimiters = slices.DeleteFunc(m.deltaLimiters, func(l *cardinalityLimiter) bool {
+		for _, released := range limiters {
+			if l == released {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WithCardinalityReset resets the attribute sets tracked for the cardinality
+// limit at every collection, for instruments that selector exports with delta
+// temporality. The SDK forgets delta series after each collection, so without
+// a reset the limit would apply to every set seen over the registry's lifetime.
+// Pass Provider.TemporalitySelector when using a Provider.
+func WithCardinalityReset(selector sdkmetric.TemporalitySelector) RegistryOption {
+	return func(m *MetricRegistry) {
+		m.temporality = selector
+	}
+}
+
+// initCardinalityReset registers a callback that resets the delta limiters at
+// the start of every collection. Observable callbacks run before the SDK
+// collects its aggregations, and this one is registered before any of the
+// registry's instruments, so it runs ahead of their callbacks too.
+func (m *MetricRegistry) initCardinalityReset() {
+	if m.temporality == nil || (m.cardinalityLimit <= 0 && len(m.cardinalityLimits) == 0) {
+		return
+	}
+
+	// The instrument only gives the callback something to be registered with;
+	// it is never observed, so it is never exported.
+	trigger, err := m.meter.Int64ObservableGauge("gotel.cardinality.reset")
+	if err != nil {
+		return
+	}
+
+	_, _ = m.meter.RegisterCallback(func(context.Context, metric.Observer) error {
+		m.limitersMu.Lock()
+		limiters := append([]*cardinalityLimiter(nil), m.deltaLimiters...)
+		m.limitersMu.Unlock()
+
+		for _, l := range limiters {
+			l.reset()
+		}
+		return nil
+	}, trigger)
+}
+
+// attributes returns set if it is already tracked or fits under the limit,
+// and the overflow set otherwise.
+func (l *cardinalityLimiter) attributes(ctx context.Context, set attribute.Set) attribute.Set {
+	if l.admitted(ctx, set) {
+		return set
+	}
+	return overflowAttrs
+}
+
+// admitted reports whether set is already tracked or fits under the limit,
+// counting the measurement as an overflow when it does not.
+func (l *cardinalityLimiter) admitted(ctx context.Context, set attribute.Set) bool {
+	if l.admit(set) {
+		return true
+	}
+
+	l.mu.Lock()
+	firstOverflow := !l.overflowed
+	l.overflowed = true
+	l.mu.Unlock()
+
+	l.registry.recordOverflow(ctx, l.name, l.limit, firstOverflow)
+	return false
+}
+
+// admit tracks set if it fits under the limit and reports whether it is
+// tracked, without recording anything when it is not.
+func (l *cardinalityLimiter) admit(set attribute.Set) bool {
+	key := set.Equivalent()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[key]; ok {
+		return true
+	}
+	if len(l.seen) < l.limit {
+		l.seen[key] = struct{}{}
+		return true
+	}
+	return false
+}
+
+// initOverflowCounter creates the overflow self-metric up front, since creating
+// instruments from within an observable callback would deadlock the SDK.
+func (m *MetricRegistry) initOverflowCounter() {
+	if m.cardinalityLimit <= 0 && len(m.cardinalityLimits) == 0 {
+		return
+	}
+
+	name, err := m.generateMetricName("metric_cardinality_overflow_total", "")
+	if err != nil {
+		return
+	}
+
+	m.overflowCounter, _ = m.meter.Int64Counter(
+		name,
+		metric.WithDescription("Number of measurements collapsed into the overflow series"),
+	)
+}
+
+// recordOverflow increments the overflow self-metric and logs a warning the
+// first time an instrument exceeds its limit.
+func (m *MetricRegistry) recordOverflow(ctx context.Context, name string, limit int, first bool) {
+	if m.overflowCounter != nil {
+		m.overflowCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("metric", name)))
+	}
+
+	if first && m.logger != nil {
+		m.logger.Warn(ctx, "Metric cardinality limit reached, collapsing new series into overflow",
+			zap.String("metric", name),
+			zap.Int("limit", limit),
+		)
+	}
+}
+
+// limitInt64Callback wraps callback so its observations respect the
+// instrument's cardinality limit.
+func (m *MetricRegistry) limitInt64Callback(
+	name, metricName string, kind instrumentKind, callback metric.Int64Callback,
+) metric.Int64Callback {
+	limiter := m.newCardinalityLimiter(name, metricName, kind)
+	if limiter == nil {
+		return callback
+	}
+
+	return func(ctx context.Context, o metric.Int64Observer) error {
+		return callback(ctx, &limitedInt64Observer{Int64Observer: o, ctx: ctx, limiter: limiter})
+	}
+}
+
+// limitFloat64Callback wraps callback so its observations respect the
+// instrument's cardinality limit.
+func (m *MetricRegistry) limitFloat64Callback(
+	name, metricName string, kind instrumentKind, callback metric.Float64Callback,
+) metric.Float64Callback {
+	limiter := m.newCardinalityLimiter(name, metricName, kind)
+	if limiter == nil {
+		return callback
+	}
+
+	return func(ctx context.Context, o metric.Float64Observer) error {
+		return callback(ctx, &limitedFloat64Observer{Float64Observer: o, ctx: ctx, limiter: limiter})
+	}
+}
+
+type limitedInt64Counter struct {
+	metric.Int64Counter
+	limiter *cardinalityLimiter
+}
+
+func (c *limitedInt64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	c.Int64Counter.Add(ctx, incr, metric.WithAttributeSet(c.limiter.attributes(ctx, attrs)))
+}
+
+type limitedFloat64Counter struct {
+	metric.Float64Counter
+	limiter *cardinalityLimiter
+}
+
+func (c *limitedFloat64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	c.Float64Counter.Add(ctx, incr, metric.WithAttributeSet(c.limiter.attributes(ctx, attrs)))
+}
+
+type limitedInt64UpDownCounter struct {
+	metric.Int64UpDownCounter
+	limiter *cardinalityLimiter
+}
+
+func (c *limitedInt64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	c.Int64UpDownCounter.Add(ctx, incr, metric.WithAttributeSet(c.limiter.attributes(ctx, attrs)))
+}
+
+type limitedFloat64UpDownCounter struct {
+	metric.Float64UpDownCounter
+	limiter *cardinalityLimiter
+}
+
+func (c *limitedFloat64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	c.Float64UpDownCounter.Add(ctx, incr, metric.WithAttributeSet(c.limiter.attributes(ctx, attrs)))
+}
+
+type limitedInt64Histogram struct {
+	metric.Int64Histogram
+	limiter *cardinalityLimiter
+}
+
+func (h *limitedInt64Histogram) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	h.Int64Histogram.Record(ctx, value, metric.WithAttributeSet(h.limiter.attributes(ctx, attrs)))
+}
+
+type limitedFloat64Histogram struct {
+	metric.Float64Histogram
+	limiter *cardinalityLimiter
+}
+
+func (h *limitedFloat64Histogram) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	h.Float64Histogram.Record(ctx, value, metric.WithAttributeSet(h.limiter.attributes(ctx, attrs)))
+}
+
+type limitedInt64Gauge struct {
+	metric.Int64Gauge
+	limiter *cardinalityLimiter
+}
+
+func (g *limitedInt64Gauge) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	g.Int64Gauge.Record(ctx, value, metric.WithAttributeSet(g.limiter.attributes(ctx, attrs)))
+}
+
+type limitedFloat64Gauge struct {
+	metric.Float64Gauge
+	limiter *cardinalityLimiter
+}
+
+func (g *limitedFloat64Gauge) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	g.Float64Gauge.Record(ctx, value, metric.WithAttributeSet(g.limiter.attributes(ctx, attrs)))
+}
+
+type limitedInt64Observer struct {
+	metric.Int64Observer
+	ctx     context.Context
+	limiter *cardinalityLimiter
+}
+
+func (o *limitedInt64Observer) Observe(value int64, options ...metric.ObserveOption) {
+	attrs := metric.NewObserveConfig(options).Attributes()
+	o.Int64Observer.Observe(value, metric.WithAttributeSet(o.limiter.attributes(o.ctx, attrs)))
+}
+
+type limitedFloat64Observer struct {
+	metric.Float64Observer
+	ctx     context.Context
+	limiter *cardinalityLimiter
+}
+
+func (o *limitedFloat64Observer) Observe(value float64, options ...metric.ObserveOption) {
+	attrs := metric.NewObserveConfig(options).Attributes()
+	o.Float64Observer.Observe(value, metric.WithAttributeSet(o.limiter.attributes(o.ctx, attrs)))
+}
@@ -0,0 +1,170 @@
+package gotel_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Cardinality limits", func() {
+	var (
+		ctx    context.Context
+		reader *sdkmetric.ManualReader
+		meter  metric.Meter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+		meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("cardinality-test")
+	})
+
+	routeAttrs := func(route string) metric.MeasurementOption {
+		return metric.WithAttributes(attribute.String("route", route))
+	}
+
+	It("should collapse series beyond the per-metric limit into the overflow series", func() {
+		registry := gotel.NewMetricRegistry(meter, "app",
+			gotel.WithCardinalityLimit(100),
+			gotel.WithMetricCardinalityLimit("requests_total", 2),
+		)
+
+		counter, err := registry.Counter("requests_total", "Total requests")
+		Expect(err).NotTo(HaveOccurred())
+		for _, route := range []string{"/a", "/b", "/c", "/d", "/a"} {
+			counter.Add(ctx, 1, routeAttrs(route))
+		}
+
+		metrics := collectMetrics(ctx, reader)
+
+		sum := metrics["app_requests_total"].Data.(metricdata.Sum[int64])
+		values := make(map[string]int64)
+		for _, dp := range sum.DataPoints {
+			if route, ok := dp.Attributes.Value("route"); ok {
+				values[route.AsString()] = dp.Value
+			} else {
+				overflow, ok := dp.Attributes.Value("otel.metric.overflow")
+				Expect(ok).To(BeTrue())
+				Expect(overflow.AsBool()).To(BeTrue())
+				values["overflow"] = dp.Value
+			}
+		}
+		Expect(values).To(Equal(map[string]int64{"/a": 2, "/b": 1, "overflow": 2}))
+
+		overflow := metrics["app_metric_cardinality_overflow_total"].Data.(metricdata.Sum[int64])
+		Expect(overflow.DataPoints).To(HaveLen(1))
+		Expect(overflow.DataPoints[0].Value).To(Equal(int64(2)))
+		Expect(overflow.DataPoints[0].Attributes.HasValue("metric")).To(BeTrue())
+	})
+
+	It("should apply the default limit to histograms and observable gauges", func() {
+		registry := gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(1))
+
+		histogram, err := registry.Histogram("latency_seconds", "Latency")
+		Expect(err).NotTo(HaveOccurred())
+		histogram.Record(ctx, 0.1, routeAttrs("/a"))
+		histogram.Record(ctx, 0.2, routeAttrs("/b"))
+
+		_, err = registry.Gauge("queue_depth", "Queue depth", func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(1, routeAttrs("/a"))
+			o.Observe(2, routeAttrs("/b"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["latency_seconds"].Data.(metricdata.Histogram[float64]).DataPoints).To(HaveLen(2))
+		Expect(metrics["queue_depth"].Data.(metricdata.Gauge[int64]).DataPoints).To(HaveLen(2))
+		Expect(metrics["metric_cardinality_overflow_total"].Data.(metricdata.Sum[int64]).DataPoints).To(HaveLen(2))
+	})
+
+	It("should record overflow from an observable gauge callback without deadlocking", func() {
+		registry := gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(1))
+
+		_, err := registry.Gauge("pool_size", "Pool size", func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(1, routeAttrs("/a"))
+			o.Observe(2, routeAttrs("/b"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		collected := make(chan map[string]metricdata.Metrics, 1)
+		go func() {
+			defer GinkgoRecover()
+			collectMetrics(ctx, reader)
+			collected <- collectMetrics(ctx, reader)
+		}()
+
+		var metrics map[string]metricdata.Metrics
+		Eventually(collected).Should(Receive(&metrics))
+		Expect(metrics["pool_size"].Data.(metricdata.Gauge[int64]).DataPoints).To(HaveLen(2))
+
+		overflow := metrics["metric_cardinality_overflow_total"].Data.(metricdata.Sum[int64])
+		Expect(overflow.DataPoints).To(HaveLen(1))
+		Expect(overflow.DataPoints[0].Value).To(BeNumerically(">=", 1))
+	})
+
+	It("should reset the tracked sets every collection for delta instruments", func() {
+		delta := func(sdkmetric.InstrumentKind) metricdata.Temporality { return metricdata.DeltaTemporality }
+		reader = sdkmetric.NewManualReader(sdkmetric.WithTemporalitySelector(delta))
+		meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("cardinality-test")
+		registry := gotel.NewMetricRegistry(meter, "",
+			gotel.WithCardinalityLimit(1),
+			gotel.WithCardinalityReset(delta),
+		)
+
+		counter, err := registry.Counter("requests_total", "Total requests")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, route := range []string{"/a", "/b", "/c"} {
+			counter.Add(ctx, 1, routeAttrs(route))
+
+			metrics := collectMetrics(ctx, reader)
+			Expect(metrics).NotTo(HaveKey("metric_cardinality_overflow_total"))
+			Expect(metrics).NotTo(HaveKey("gotel.cardinality.reset"))
+
+			points := metrics["requests_total"].Data.(metricdata.Sum[int64]).DataPoints
+			Expect(points).To(HaveLen(1))
+			value, _ := points[0].Attributes.Value("route")
+			Expect(value.AsString()).To(Equal(route))
+		}
+	})
+
+	It("should keep the tracked sets for cumulative instruments", func() {
+		registry := gotel.NewMetricRegistry(meter, "",
+			gotel.WithCardinalityLimit(1),
+			gotel.WithCardinalityReset(sdkmetric.DefaultTemporalitySelector),
+		)
+
+		counter, err := registry.Counter("requests_total", "Total requests")
+		Expect(err).NotTo(HaveOccurred())
+		counter.Add(ctx, 1, routeAttrs("/a"))
+		collectMetrics(ctx, reader)
+		counter.Add(ctx, 1, routeAttrs("/b"))
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["requests_total"].Data.(metricdata.Sum[int64]).DataPoints).To(HaveLen(2))
+		Expect(metrics).To(HaveKey("metric_cardinality_overflow_total"))
+	})
+
+	It("should not limit instruments when the limit is disabled", func() {
+		registry := gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(0))
+
+		counter, err := registry.Counter("requests_total", "Total requests")
+		Expect(err).NotTo(HaveOccurred())
+		for _, route := range []string{"/a", "/b", "/c"} {
+			counter.Add(ctx, 1, routeAttrs(route))
+		}
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["requests_total"].Data.(metricdata.Sum[int64]).DataPoints).To(HaveLen(3))
+		Expect(metrics).NotTo(HaveKey("metric_cardinality_overflow_total"))
+	})
+})
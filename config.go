@@ -1,9 +1,12 @@
 package gotel
 
 import (
+	"fmt"
 	"maps"
 	"time"
 
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"google.golang.org/grpc/credentials"
 )
 
@@ -20,6 +23,10 @@ type config struct {
 	// trace propagation behavior.
 	Tracing *TracingConfig
 
+	// Metrics configures how metrics are collected and exported, including the
+	// periodic export interval and any additional readers.
+	Metrics *MetricsConfig
+
 	// Logging configures application logging, including log level and
 	// integration with OpenTelemetry logging.
 	Logging *LoggingConfig
@@ -28,6 +35,14 @@ type config struct {
 	// to an OTLP collector.
 	Security *SecurityConfig
 
+	// StartupProbe configures an optional connectivity check against the OTLP
+	// collector performed by NewProvider.
+	StartupProbe *StartupProbeConfig
+
+	// Shutdown configures how long graceful shutdown may take before it is
+	// abandoned.
+	Shutdown *ShutdownConfig
+
 	// ResourceAttrs contains additional custom key-value attributes describing
 	// the resource emitting telemetry.
 	ResourceAttrs map[string]any
@@ -45,12 +60,52 @@ type ExporterConfig struct {
 
 type TracingConfig struct {
 	SamplingRatio float64 // (1.0 = always, 0.0 = never).
+	SpanMetrics   SpanMetricsConfig
+	ServiceGraph  ServiceGraphConfig
+}
+
+type MetricsConfig struct {
+	Interval             time.Duration
+	ExportTimeout        time.Duration
+	Readers              []sdkmetric.Reader
+	Views                []MetricView
+	Temporality          TemporalityPreference
+	TemporalityOverrides map[sdkmetric.InstrumentKind]metricdata.Temporality
+	Exemplars            ExemplarConfig
+	Prometheus           bool // Serve a Prometheus pull endpoint via Provider.MetricsHandler.
+	Runtime              RuntimeMetricsConfig
+}
+
+type RuntimeMetricsConfig struct {
+	Enabled         bool
+	MinReadInterval time.Duration // Minimum time between two reads of runtime/metrics.
 }
 
 type LoggingConfig struct {
 	Level string
 }
 
+type StartupProbeConfig struct {
+	Enabled  bool
+	Timeout  time.Duration
+	FailMode ProbeFailMode
+}
+
+// ProbeFailMode controls what NewProvider does when the startup probe fails.
+type ProbeFailMode int
+
+const (
+	// ProbeFailError makes NewProvider return an error.
+	ProbeFailError ProbeFailMode = iota
+	// ProbeFailWarn logs a warning and lets NewProvider succeed.
+	ProbeFailWarn
+)
+
+// ShutdownConfig controls graceful shutdown performed by Run.
+type ShutdownConfig struct {
+	Timeout time.Duration // Deadline for draining servers and shutting down the Provider.
+}
+
 type ServiceInfo struct {
 	Name        string
 	Version     string
@@ -78,6 +133,15 @@ func DefaultConfig(opts ...Option) *config {
 		Security:      &SecurityConfig{Insecure: true},
 		Tracing:       &TracingConfig{SamplingRatio: 1.0},
 		Logging:       &LoggingConfig{Level: "debug"},
+		Metrics: &MetricsConfig{
+			Interval:             15 * time.Second,
+			ExportTimeout:        30 * time.Second,
+			Temporality:          TemporalityCumulative,
+			TemporalityOverrides: make(map[sdkmetric.InstrumentKind]metricdata.Temporality),
+			Runtime:              RuntimeMetricsConfig{Enabled: false, MinReadInterval: DefaultRuntimeReadInterval},
+		},
+		Shutdown:     &ShutdownConfig{Timeout: 15 * time.Second},
+		StartupProbe: &StartupProbeConfig{Enabled: false, Timeout: 5 * time.Second},
 		Exporter: &ExporterConfig{
 			Endpoint:      "localhost:4317",
 			BatchTimeout:  5 * time.Second,
@@ -93,6 +157,17 @@ func DefaultConfig(opts ...Option) *config {
 	return conf
 }
 
+// validate reports configuration errors before any SDK provider is built, so
+// an invalid configuration never leaves providers running or installed globally.
+func (c *config) validate() error {
+	for _, view := range c.Metrics.Views {
+		if err := view.validate(); err != nil {
+			return fmt.Errorf("invalid metric view: %w", err)
+		}
+	}
+	return nil
+}
+
 // WithServiceInfo configures the core service identification attributes.
 func WithServiceInfo(name, version, environment string) Option {
 	return func(c *config) {
@@ -151,6 +226,125 @@ func WithSamplingRatio(ratio float64) Option {
 	}
 }
 
+// WithSpanMetrics enables metrics derived from finished spans: call counts and
+// duration histograms keyed by service name, span name, kind, status code and
+// the given span attribute keys.
+func WithSpanMetrics(dimensions ...string) Option {
+	return func(c *config) {
+		c.Tracing.SpanMetrics.Enabled = true
+		c.Tracing.SpanMetrics.Dimensions = append(c.Tracing.SpanMetrics.Dimensions, dimensions...)
+	}
+}
+
+// WithSpanMetricsBuckets sets the span duration histogram boundaries in seconds.
+func WithSpanMetricsBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.Tracing.SpanMetrics.Buckets = buckets
+	}
+}
+
+// WithSpanMetricsCardinalityLimit caps the distinct series of each span metric.
+func WithSpanMetricsCardinalityLimit(limit int) Option {
+	return func(c *config) {
+		c.Tracing.SpanMetrics.CardinalityLimit = limit
+	}
+}
+
+// WithServiceGraph enables service graph metrics built from client and server
+// spans. Unpaired spans wait up to wait for their peer and at most maxItems
+// are kept; zero values keep DefaultServiceGraphWait and DefaultServiceGraphMaxItems.
+func WithServiceGraph(wait time.Duration, maxItems int) Option {
+	return func(c *config) {
+		c.Tracing.ServiceGraph.Enabled = true
+		c.Tracing.ServiceGraph.Wait = wait
+		c.Tracing.ServiceGraph.MaxItems = maxItems
+	}
+}
+
+// WithMetricInterval sets how often metrics are collected and pushed to the exporter.
+func WithMetricInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.Metrics.Interval = interval
+	}
+}
+
+// WithMetricExportTimeout sets the maximum duration of a single periodic metric export.
+func WithMetricExportTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.Metrics.ExportTimeout = timeout
+	}
+}
+
+// WithMetricReaders attaches additional readers to the meter provider, e.g. a
+// sdkmetric.ManualReader that can be collected on demand at the end of a batch job.
+func WithMetricReaders(readers ...sdkmetric.Reader) Option {
+	return func(c *config) {
+		c.Metrics.Readers = append(c.Metrics.Readers, readers...)
+	}
+}
+
+// WithMetricViews adds views that rename instruments, restrict their attribute
+// keys, change their histogram aggregation or drop them entirely.
+func WithMetricViews(views ...MetricView) Option {
+	return func(c *config) {
+		c.Metrics.Views = append(c.Metrics.Views, views...)
+	}
+}
+
+// WithTemporality sets the aggregation temporality preference of the metric exporter.
+func WithTemporality(preference TemporalityPreference) Option {
+	return func(c *config) {
+		c.Metrics.Temporality = preference
+	}
+}
+
+// WithTemporalityOverride forces the temporality of a single instrument kind,
+// regardless of the configured preference.
+func WithTemporalityOverride(kind sdkmetric.InstrumentKind, temporality metricdata.Temporality) Option {
+	return func(c *config) {
+		c.Metrics.TemporalityOverrides[kind] = temporality
+	}
+}
+
+// WithExemplarFilter sets which measurements may be sampled as exemplars.
+// The default keeps measurements recorded within a sampled span.
+func WithExemplarFilter(filter ExemplarFilter) Option {
+	return func(c *config) {
+		c.Metrics.Exemplars.Filter = filter
+	}
+}
+
+// WithExemplarReservoirSize sets how many exemplars are kept per series for
+// non-histogram instruments. Explicit bucket histograms keep one per bucket.
+func WithExemplarReservoirSize(size int) Option {
+	return func(c *config) {
+		c.Metrics.Exemplars.ReservoirSize = size
+	}
+}
+
+// WithPrometheus enables or disables the Prometheus pull endpoint returned by
+// Provider.MetricsHandler. Metrics are still pushed over OTLP when enabled.
+func WithPrometheus(enabled bool) Option {
+	return func(c *config) {
+		c.Metrics.Prometheus = enabled
+	}
+}
+
+// WithRuntimeMetrics enables Go runtime metrics such as goroutine count, memory
+// usage, GC pauses and scheduler latency. runtime/metrics is read at most once
+// per minReadInterval; zero keeps the default of DefaultRuntimeReadInterval.
+// The GC pause and scheduler latency histograms are only reported by the
+// provider's own exporters; readers passed WithMetricReaders report them when
+// created with sdkmetric.WithProducer(NewRuntimeProducer(...)).
+func WithRuntimeMetrics(minReadInterval time.Duration) Option {
+	return func(c *config) {
+		c.Metrics.Runtime.Enabled = true
+		if minReadInterval > 0 {
+			c.Metrics.Runtime.MinReadInterval = minReadInterval
+		}
+	}
+}
+
 // WithResourceAttr adds or updates a single resource attribute (key-value pair).
 func WithResourceAttr(key string, value any) Option {
 	return func(c *config) {
@@ -195,3 +389,22 @@ func WithLogLevel(level string) Option {
 		c.Logging.Level = level
 	}
 }
+
+// WithStartupProbe enables a check, run by NewProvider, that the OTLP collector
+// is reachable and accepts an empty export for traces, metrics and logs.
+// The probe is skipped in debug mode.
+func WithStartupProbe(timeout time.Duration, failMode ProbeFailMode) Option {
+	return func(c *config) {
+		c.StartupProbe.Enabled = true
+		c.StartupProbe.Timeout = timeout
+		c.StartupProbe.FailMode = failMode
+	}
+}
+
+// WithShutdownTimeout sets the deadline for draining servers and flushing
+// telemetry when the process is shutting down.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.Shutdown.Timeout = timeout
+	}
+}
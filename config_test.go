@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/iamBelugax/gotel"
@@ -38,6 +39,12 @@ var _ = Describe("Config", func() {
 			Expect(config.Debug).To(BeFalse())
 			Expect(config.ResourceAttrs).To(BeEmpty())
 			Expect(config.Tracing.SamplingRatio).To(Equal(samplingRate))
+			Expect(config.Shutdown.Timeout).To(Equal(15 * time.Second))
+			Expect(config.Metrics.Interval).To(Equal(15 * time.Second))
+			Expect(config.Metrics.ExportTimeout).To(Equal(30 * time.Second))
+			Expect(config.Metrics.Readers).To(BeEmpty())
+			Expect(config.Metrics.Runtime.Enabled).To(BeFalse())
+			Expect(config.Metrics.Runtime.MinReadInterval).To(Equal(gotel.DefaultRuntimeReadInterval))
 		})
 	})
 
@@ -108,6 +115,22 @@ var _ = Describe("Config", func() {
 			Expect(config.Logging.Level).To(Equal("info"))
 		})
 
+		It("should apply metric collection options", func() {
+			reader := sdkmetric.NewManualReader()
+			config := gotel.DefaultConfig(
+				gotel.WithMetricInterval(time.Second),
+				gotel.WithMetricExportTimeout(500*time.Millisecond),
+				gotel.WithMetricReaders(reader),
+				gotel.WithRuntimeMetrics(5*time.Second),
+			)
+
+			Expect(config.Metrics.Interval).To(Equal(time.Second))
+			Expect(config.Metrics.ExportTimeout).To(Equal(500 * time.Millisecond))
+			Expect(config.Metrics.Readers).To(ConsistOf(reader))
+			Expect(config.Metrics.Runtime.Enabled).To(BeTrue())
+			Expect(config.Metrics.Runtime.MinReadInterval).To(Equal(5 * time.Second))
+		})
+
 		It("should clamp sampling ratio to valid range", func() {
 			configHigh := gotel.DefaultConfig(gotel.WithSamplingRatio(1.5))
 			Expect(configHigh.Tracing.SamplingRatio).To(Equal(1.0))
@@ -0,0 +1,92 @@
+package gotel
+
+import (
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// MetricConvention selects the names and attribute keys CommonMetrics emits.
+type MetricConvention int
+
+const (
+	// ConventionLegacy emits the Prometheus-style names, such as
+	// http_request_duration_seconds, with the method, route and status_code keys.
+	ConventionLegacy MetricConvention = iota
+	// ConventionSemconv emits the OpenTelemetry semantic convention names, such as
+	// http.server.request.duration, with keys like http.request.method.
+	ConventionSemconv
+	// ConventionBoth emits both sets side by side, so dashboards and alerts can be
+	// migrated before the legacy names are turned off.
+	ConventionBoth
+)
+
+// legacy reports whether the Prometheus-style metrics are emitted.
+func (c MetricConvention) legacy() bool {
+	return c == ConventionLegacy || c == ConventionBoth
+}
+
+// semconv reports whether the semantic convention metrics are emitted.
+func (c MetricConvention) semconv() bool {
+	return c == ConventionSemconv || c == ConventionBoth
+}
+
+// CommonMetricsOption configures NewCommonMetrics.
+type CommonMetricsOption func(*commonMetricsConfig)
+
+type commonMetricsConfig struct {
+	convention MetricConvention
+}
+
+// WithMetricConvention selects the metric names CommonMetrics emits. The
+// default is ConventionLegacy.
+func WithMetricConvention(convention MetricConvention) CommonMetricsOption {
+	return func(c *commonMetricsConfig) {
+		c.convention = convention
+	}
+}
+
+// unprefixed returns a registry sharing m's meter, logger and cardinality
+// limits but without a prefix or naming policy, since semantic convention
+// names are fixed.
+func (m *MetricRegistry) unprefixed() *MetricRegistry {
+	return &MetricRegistry{
+		meter:             m.meter,
+		logger:            m.logger,
+		instruments:       make(map[string]registeredInstrument),
+		cardinalityLimit:  m.cardinalityLimit,
+		cardinalityLimits: m.cardinalityLimits,
+		overflowCounter:   m.overflowCounter,
+	}
+}
+
+// initSemconvMetrics creates the semantic convention HTTP and database metrics.
+func (cm *CommonMetrics) initSemconvMetrics(registry *MetricRegistry) error {
+	var err error
+
+	cm.HTTPServerRequestDuration, err = registry.Histogram(
+		semconv.HTTPServerRequestDurationName,
+		semconv.HTTPServerRequestDurationDescription,
+		metric.WithUnit(semconv.HTTPServerRequestDurationUnit),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10),
+	)
+	if err != nil {
+		return err
+	}
+
+	cm.HTTPServerActiveRequests, err = registry.UpDownCounter(
+		semconv.HTTPServerActiveRequestsName,
+		semconv.HTTPServerActiveRequestsDescription,
+		metric.WithUnit(semconv.HTTPServerActiveRequestsUnit),
+	)
+	if err != nil {
+		return err
+	}
+
+	cm.DBClientOperationDuration, err = registry.Histogram(
+		semconv.DBClientOperationDurationName,
+		semconv.DBClientOperationDurationDescription,
+		metric.WithUnit(semconv.DBClientOperationDurationUnit),
+		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10),
+	)
+	return err
+}
@@ -0,0 +1,116 @@
+package gotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Metric conventions", func() {
+	var (
+		ctx    context.Context
+		reader *sdkmetric.ManualReader
+	)
+
+	// exercise serves a few requests through the middleware and runs one query
+	// through a dbTracer, then returns the collected metrics.
+	exercise := func(convention gotel.MetricConvention) map[string]metricdata.Metrics {
+		meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("conventions-test")
+		metrics, err := gotel.NewCommonMetrics(gotel.NewMetricRegistry(meter, "app"), gotel.WithMetricConvention(convention))
+		Expect(err).NotTo(HaveOccurred())
+
+		tracer := gotel.NewTracer(noop.NewTracerProvider().Tracer("conventions-test"))
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /orders/{id}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})
+		mux.HandleFunc("/fail", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		handler := gotel.NewHTTPMiddleware("svc", tracer, metrics).Handler(mux)
+		for _, path := range []string{"/orders/42", "/fail", "/missing"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+		}
+
+		Expect(gotel.NewDBTracer(tracer, metrics, "shop", "postgresql").Trace(ctx, "SELECT 1", func() error {
+			return nil
+		})).To(Succeed())
+
+		return collectMetrics(ctx, reader)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+	})
+
+	It("should emit only the legacy names by default", func() {
+		metrics := exercise(gotel.ConventionLegacy)
+		Expect(metrics).To(HaveKey("app_http_request_duration_seconds"))
+		Expect(metrics).To(HaveKey("app_db_queries_total"))
+		Expect(metrics).NotTo(HaveKey("http.server.request.duration"))
+		Expect(metrics).NotTo(HaveKey("db.client.operation.duration"))
+	})
+
+	It("should emit unprefixed semantic convention metrics with units and attributes", func() {
+		metrics := exercise(gotel.ConventionSemconv)
+		Expect(metrics).NotTo(HaveKey("app_http_request_duration_seconds"))
+		Expect(metrics).NotTo(HaveKey("app_db_queries_total"))
+
+		duration := metrics["http.server.request.duration"]
+		Expect(duration.Unit).To(Equal("s"))
+		var points [][]attribute.KeyValue
+		for _, point := range duration.Data.(metricdata.Histogram[float64]).DataPoints {
+			Expect(point.Count).To(Equal(uint64(1)))
+			points = append(points, point.Attributes.ToSlice())
+		}
+		Expect(points).To(ConsistOf(
+			ConsistOf(
+				attribute.String("http.request.method", "POST"),
+				attribute.String("http.route", "/orders/{id}"),
+				attribute.Int("http.response.status_code", http.StatusCreated),
+				attribute.String("url.scheme", "http"),
+			),
+			ConsistOf(
+				attribute.String("http.request.method", "POST"),
+				attribute.String("http.route", "/fail"),
+				attribute.Int("http.response.status_code", http.StatusServiceUnavailable),
+				attribute.String("url.scheme", "http"),
+				attribute.String("error.type", "503"),
+			),
+			ConsistOf(
+				attribute.String("http.request.method", "POST"),
+				attribute.Int("http.response.status_code", http.StatusNotFound),
+				attribute.String("url.scheme", "http"),
+			),
+		))
+
+		active := metrics["http.server.active_requests"]
+		Expect(active.Unit).To(Equal("{request}"))
+		Expect(active.Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(BeZero())
+
+		db := metrics["db.client.operation.duration"].Data.(metricdata.Histogram[float64]).DataPoints[0]
+		Expect(db.Attributes.ToSlice()).To(ConsistOf(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.namespace", "shop"),
+		))
+	})
+
+	It("should emit both sets side by side during migration", func() {
+		metrics := exercise(gotel.ConventionBoth)
+		Expect(metrics).To(HaveKey("app_http_requests_total"))
+		Expect(metrics).To(HaveKey("app_db_query_duration_seconds"))
+		Expect(metrics).To(HaveKey("http.server.request.duration"))
+		Expect(metrics).To(HaveKey("db.client.operation.duration"))
+	})
+})
@@ -6,27 +6,40 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // dbTracer provides tracing and metrics for database operations.
 type dbTracer struct {
-	dbName  string
-	dbType  string
-	tracer  *Tracer
-	metrics *CommonMetrics
+	dbName        string
+	dbType        string
+	tracer        *Tracer
+	metrics       *CommonMetrics
+	queriesTotal  *BoundCounter
+	queryDuration *BoundHistogram
+
+	operationDuration *BoundHistogram
 }
 
 // NewDBTracer creates a new DBTracer instance for a given database.
 func NewDBTracer(tracer *Tracer, metrics *CommonMetrics, dbName, dbType string) *dbTracer {
+	attrs := []attribute.KeyValue{
+		attribute.String("db_name", dbName),
+		attribute.String("db_type", dbType),
+	}
+
 	return &dbTracer{
-		dbName:  dbName,
-		dbType:  dbType,
-		tracer:  tracer,
-		metrics: metrics,
+		dbName:        dbName,
+		dbType:        dbType,
+		tracer:        tracer,
+		metrics:       metrics,
+		queriesTotal:  BindCounter(metrics.DBQueriesTotal, attrs...),
+		queryDuration: BindHistogram(metrics.DBQueryDuration, attrs...),
+		operationDuration: BindHistogram(metrics.DBClientOperationDuration,
+			semconv.DBSystemKey.String(dbType),
+			semconv.DBNamespaceKey.String(dbName),
+		),
 	}
 }
 
@@ -46,16 +59,12 @@ func (dt *dbTracer) Trace(ctx context.Context, query string, fn func() error) er
 	err := fn()
 	duration := time.Since(start)
 
-	attrs := []attribute.KeyValue{
-		attribute.String("db_name", dt.dbName),
-		attribute.String("db_type", dt.dbType),
-	}
-	dt.metrics.DBQueriesTotal.Add(ctx, 1, metric.WithAttributes(attrs...))
-	dt.metrics.DBQueryDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+	dt.queriesTotal.Add(ctx, 1)
+	dt.queryDuration.Record(ctx, duration.Seconds())
+	dt.operationDuration.Record(ctx, duration.Seconds())
 
 	if err != nil {
 		span.WithError(err)
-		span.WithStatus(codes.Error, err.Error())
 	}
 
 	return err
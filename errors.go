@@ -0,0 +1,142 @@
+package gotel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error is an error annotated with telemetry attributes. When it is recorded
+// with Span.WithError or logged through ZapLogger, anywhere in an error chain,
+// its attributes, the type of the wrapped error and its optional stack trace
+// are added to the telemetry.
+type Error struct {
+	err   error
+	attrs []attribute.KeyValue
+	stack []byte
+}
+
+// WrapError annotates err with attributes. It returns nil if err is nil.
+func WrapError(err error, attrs ...attribute.KeyValue) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{err: err, attrs: attrs}
+}
+
+// WrapErrorWithStack is like WrapError but also captures the current stack trace.
+func WrapErrorWithStack(err error, attrs ...attribute.KeyValue) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{err: err, attrs: attrs, stack: debug.Stack()}
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error, so errors.Is and errors.As see through Error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Attributes returns the attributes the error was annotated with.
+func (e *Error) Attributes() []attribute.KeyValue {
+	return e.attrs
+}
+
+// Stack returns the stack trace captured by WrapErrorWithStack, if any.
+func (e *Error) Stack() []byte {
+	return e.stack
+}
+
+// errorDetails is the telemetry extracted from an error chain.
+type errorDetails struct {
+	attrs     []attribute.KeyValue
+	errorType string
+	stack     []byte
+}
+
+// extractErrorDetails collects the attributes of every Error in err's chain,
+// with outer attributes taking precedence, and the type of the innermost
+// wrapped error.
+func extractErrorDetails(err error) errorDetails {
+	details := errorDetails{errorType: fmt.Sprintf("%T", err)}
+
+	var annotated []*Error
+	for next := err; ; {
+		var e *Error
+		if !errors.As(next, &e) {
+			break
+		}
+		annotated = append(annotated, e)
+		details.errorType = fmt.Sprintf("%T", e.err)
+		next = e.err
+	}
+
+	for i := len(annotated) - 1; i >= 0; i-- {
+		details.attrs = append(details.attrs, annotated[i].attrs...)
+		if details.stack == nil {
+			details.stack = annotated[i].stack
+		}
+	}
+	return details
+}
+
+// ErrorClassifier reports whether err should mark a span as failed. Errors it
+// rejects are still recorded on the span, but leave its status unset.
+type ErrorClassifier func(err error) bool
+
+// DefaultErrorClassifier treats every error as a failure except cancellations
+// and empty query results, which are expected outcomes rather than faults.
+func DefaultErrorClassifier(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, sql.ErrNoRows)
+}
+
+// WithErrorClassifier sets the classifier used by spans started through the
+// Tracer to decide whether an error sets the span status to error.
+func WithErrorClassifier(classifier ErrorClassifier) TracerOption {
+	return func(t *Tracer) {
+		t.classifier = classifier
+	}
+}
+
+// expandErrorFields appends the details of Error values found in zap error
+// fields, so annotated errors are logged with their attributes.
+func expandErrorFields(fields []zap.Field) []zap.Field {
+	// Cap the slice so appending never writes into the caller's backing array.
+	expanded := fields[:len(fields):len(fields)]
+	for _, field := range fields {
+		if field.Type != zapcore.ErrorType {
+			continue
+		}
+
+		err, ok := field.Interface.(error)
+		if !ok {
+			continue
+		}
+
+		var e *Error
+		if !errors.As(err, &e) {
+			continue
+		}
+
+		details := extractErrorDetails(err)
+		expanded = append(expanded, zap.String(string(semconv.ErrorTypeKey), details.errorType))
+		for _, kv := range details.attrs {
+			expanded = append(expanded, zap.Any(string(kv.Key), kv.Value.AsInterface()))
+		}
+		if details.stack != nil {
+			expanded = append(expanded, zap.String(string(semconv.ExceptionStacktraceKey), string(details.stack)))
+		}
+	}
+	return expanded
+}
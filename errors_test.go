@@ -0,0 +1,140 @@
+package gotel_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/iamBelugax/gotel"
+)
+
+type quotaError struct{}
+
+func (quotaError) Error() string { return "quota exceeded" }
+
+var _ = Describe("Error attribution", func() {
+	var (
+		ctx      context.Context
+		recorder *tracetest.SpanRecorder
+		provider *sdktrace.TracerProvider
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	})
+
+	It("should return nil when wrapping a nil error", func() {
+		Expect(gotel.WrapError(nil, attribute.String("user.id", "42"))).To(BeNil())
+		Expect(gotel.WrapErrorWithStack(nil)).To(BeNil())
+	})
+
+	It("should keep the wrapped error visible to errors.Is and errors.As", func() {
+		err := gotel.WrapError(sql.ErrNoRows, attribute.String("table", "users"))
+		Expect(err).To(MatchError(sql.ErrNoRows))
+
+		var annotated *gotel.Error
+		Expect(errors.As(fmt.Errorf("lookup: %w", err), &annotated)).To(BeTrue())
+		Expect(annotated.Attributes()).To(ConsistOf(attribute.String("table", "users")))
+		Expect(annotated.Stack()).To(BeNil())
+	})
+
+	It("should add the attributes, error type and stack of wrapped errors to the span", func() {
+		inner := gotel.WrapErrorWithStack(quotaError{}, attribute.String("tenant", "acme"), attribute.Int("limit", 10))
+		err := gotel.WrapError(fmt.Errorf("charge: %w", inner), attribute.Int("limit", 20))
+
+		_, span := gotel.NewTracer(provider.Tracer("errors-test")).StartSpan(ctx, "charge")
+		span.EndWithError(err)
+
+		ended := recorder.Ended()[0]
+		Expect(ended.Status().Code).To(Equal(codes.Error))
+		Expect(ended.Status().Description).To(Equal("charge: quota exceeded"))
+
+		attrs := spanAttrs(ended)
+		Expect(attrs["tenant"].AsString()).To(Equal("acme"))
+		Expect(attrs["limit"].AsInt64()).To(Equal(int64(20)))
+		Expect(attrs["error.type"].AsString()).To(Equal("gotel_test.quotaError"))
+
+		Expect(ended.Events()).To(HaveLen(1))
+		var exceptionTypes []string
+		for _, kv := range ended.Events()[0].Attributes {
+			if kv.Key == "exception.type" {
+				exceptionTypes = append(exceptionTypes, kv.Value.AsString())
+			}
+		}
+		Expect(exceptionTypes).To(ConsistOf("gotel_test.quotaError"))
+		message, _ := eventAttr(ended, "exception", "exception.message")
+		Expect(message.AsString()).To(Equal("charge: quota exceeded"))
+		stack, ok := eventAttr(ended, "exception", "exception.stacktrace")
+		Expect(ok).To(BeTrue())
+		Expect(stack.AsString()).To(ContainSubstring("errors_test.go"))
+	})
+
+	It("should log the attributes, error type and stack of wrapped errors", func() {
+		core, logs := observer.New(zapcore.DebugLevel)
+		logger := gotel.NewZapLoggerWithCore(core)
+
+		inner := gotel.WrapErrorWithStack(quotaError{}, attribute.String("tenant", "acme"), attribute.Int("limit", 10))
+		err := gotel.WrapError(fmt.Errorf("charge: %w", inner), attribute.Int("limit", 20))
+
+		fields := make([]zap.Field, 1, 8)
+		fields[0] = zap.Error(err)
+		logger.Error(ctx, "charge failed", fields...)
+
+		Expect(fields[:cap(fields)][1:]).To(HaveEach(zap.Field{}))
+
+		Expect(logs.All()).To(HaveLen(1))
+		logged := logs.All()[0].ContextMap()
+		Expect(logged).To(HaveKeyWithValue("error", "charge: quota exceeded"))
+		Expect(logged).To(HaveKeyWithValue("tenant", "acme"))
+		Expect(logged).To(HaveKeyWithValue("limit", int64(20)))
+		Expect(logged).To(HaveKeyWithValue("error.type", "gotel_test.quotaError"))
+		Expect(logged).To(HaveKeyWithValue("exception.stacktrace", ContainSubstring("errors_test.go")))
+	})
+
+	It("should record expected errors without marking the span as failed", func() {
+		tracer := gotel.NewTracer(provider.Tracer("errors-test"))
+
+		for _, err := range []error{context.Canceled, fmt.Errorf("find user: %w", sql.ErrNoRows)} {
+			_, span := tracer.StartSpan(ctx, "find")
+			span.EndWithError(err)
+		}
+
+		for _, ended := range recorder.Ended() {
+			Expect(ended.Status().Code).To(Equal(codes.Unset))
+			Expect(ended.Events()).To(HaveLen(1))
+			Expect(spanAttrs(ended)).NotTo(HaveKey(attribute.Key("error.type")))
+		}
+	})
+
+	It("should apply a custom classifier to spans and their children", func() {
+		ignoreQuota := func(err error) bool {
+			return !errors.As(err, new(quotaError))
+		}
+		tracer := gotel.NewTracer(provider.Tracer("errors-test"), gotel.WithErrorClassifier(ignoreQuota))
+
+		err := tracer.WithSpan(ctx, "parent", func(ctx context.Context, span *gotel.Span) error {
+			_, child := span.ChildSpan(ctx, "child")
+			child.EndWithError(context.Canceled)
+			return quotaError{}
+		})
+		Expect(err).To(MatchError(quotaError{}))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[1].Status().Code).To(Equal(codes.Unset))
+	})
+})
@@ -5,15 +5,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/iamBelugax/gotel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
-	"go.uber.org/zap"
 )
 
 var (
@@ -28,22 +24,18 @@ func main() {
 
 	provider, err := gotel.NewProvider(ctx,
 		gotel.WithEndpoint("localhost:4317"),
+		gotel.WithPrometheus(true),
 		gotel.WithResourceAttr("team", "platform"),
 		gotel.WithServiceInfo(serviceName, serviceVersion, environment),
 	)
 	if err != nil {
 		log.Fatalf("Failed to initialize OTEL provider: %v", err)
 	}
-	defer func() {
-		if err := provider.Shutdown(context.Background()); err != nil {
-			log.Printf("Failed to shutdown OTEL provider: %v", err)
-		}
-	}()
 
 	logger := provider.Logger()
 	tracer := gotel.NewTracer(provider.Tracer())
 
-	metricRegistry := gotel.NewMetricRegistry(provider.Meter(), "")
+	metricRegistry := gotel.NewMetricRegistry(provider.Meter(), "", gotel.WithRegistryLogger(logger))
 	commonMetrics, err := gotel.NewCommonMetrics(metricRegistry)
 	if err != nil {
 		log.Fatalf("Failed to create common metrics: %v", err)
@@ -64,6 +56,7 @@ func main() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", app.handleHome)
 	mux.HandleFunc("/users", app.handleUsers)
+	mux.Handle("/metrics", provider.MetricsHandler())
 
 	httpMiddleware := gotel.NewHTTPMiddleware(serviceName, tracer, commonMetrics)
 	handler := httpMiddleware.Handler(mux)
@@ -73,20 +66,8 @@ func main() {
 		Handler: handler,
 	}
 
-	go func() {
-		logger.Info(ctx, "Starting server on port 8080")
-		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			logger.Error(ctx, "Server error", zap.Error(err))
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
-
-	logger.Info(ctx, "Shutting down server...")
-	if err := server.Shutdown(context.Background()); err != nil {
-		logger.Error(ctx, "Server shutdown error", zap.Error(err))
+	if err := gotel.Run(ctx, provider, server); err != nil {
+		log.Printf("Shutdown completed with errors: %v", err)
 	}
 }
 
@@ -0,0 +1,76 @@
+package gotel
+
+import (
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+// ExemplarFilter decides which measurements are offered to exemplar reservoirs.
+type ExemplarFilter int
+
+const (
+	// ExemplarFilterTraceBased keeps exemplars only for measurements recorded
+	// with a context carrying a sampled span.
+	ExemplarFilterTraceBased ExemplarFilter = iota
+	// ExemplarFilterAlwaysOn offers every measurement to the reservoir.
+	ExemplarFilterAlwaysOn
+	// ExemplarFilterAlwaysOff disables exemplar collection.
+	ExemplarFilterAlwaysOff
+)
+
+// ExemplarConfig configures exemplar collection on the meter provider.
+type ExemplarConfig struct {
+	Filter ExemplarFilter
+
+	// ReservoirSize is the number of exemplars kept per series for aggregations
+	// other than explicit bucket histograms, which keep one per bucket. Zero
+	// uses the SDK default.
+	ReservoirSize int
+}
+
+// sdkFilter maps the filter onto its SDK implementation.
+func (c ExemplarConfig) sdkFilter() exemplar.Filter {
+	switch c.Filter {
+	case ExemplarFilterAlwaysOn:
+		return exemplar.AlwaysOnFilter
+	case ExemplarFilterAlwaysOff:
+		return exemplar.AlwaysOffFilter
+	default:
+		return exemplar.TraceBasedFilter
+	}
+}
+
+// reservoirSelector returns nil when the SDK default reservoirs should be used.
+func (c ExemplarConfig) reservoirSelector() sdkmetric.ExemplarReservoirProviderSelector {
+	if c.ReservoirSize <= 0 {
+		return nil
+	}
+
+	size := c.ReservoirSize
+	return func(agg sdkmetric.Aggregation) exemplar.ReservoirProvider {
+		if hist, ok := agg.(sdkmetric.AggregationExplicitBucketHistogram); ok && len(hist.Boundaries) > 0 {
+			return exemplar.HistogramReservoirProvider(hist.Boundaries)
+		}
+		return exemplar.FixedSizeReservoirProvider(size)
+	}
+}
+
+// exemplarView applies selector to every instrument not matched by one of
+// views, which carry the selector themselves. A separate catch-all view would
+// otherwise duplicate the streams of instruments matched by user views.
+func exemplarView(views []sdkmetric.View, selector sdkmetric.ExemplarReservoirProviderSelector) sdkmetric.View {
+	return func(inst sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+		for _, view := range views {
+			if _, ok := view(inst); ok {
+				return sdkmetric.Stream{}, false
+			}
+		}
+
+		return sdkmetric.Stream{
+			Name:                              inst.Name,
+			Description:                       inst.Description,
+			Unit:                              inst.Unit,
+			ExemplarReservoirProviderSelector: selector,
+		}, true
+	}
+}
@@ -0,0 +1,125 @@
+package gotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Exemplars", func() {
+	var (
+		ctx      context.Context
+		reader   *sdkmetric.ManualReader
+		provider *gotel.Provider
+		tracer   *gotel.Tracer
+		metrics  *gotel.CommonMetrics
+	)
+
+	newProvider := func(opts ...gotel.Option) {
+		var err error
+		reader = sdkmetric.NewManualReader()
+		opts = append(opts,
+			gotel.WithDebug(true),
+			gotel.WithMetricReaders(reader),
+			gotel.WithServiceInfo("exemplars-test", "1.0.0", "test"),
+		)
+		provider, err = gotel.NewProvider(ctx, opts...)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(provider.Shutdown, ctx)
+
+		tracer = gotel.NewTracer(provider.Tracer())
+		metrics, err = gotel.NewCommonMetrics(gotel.NewMetricRegistry(provider.Meter(), ""))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	histogramExemplars := func(name string) []metricdata.Exemplar[float64] {
+		hist := collectMetrics(ctx, reader)[name].Data.(metricdata.Histogram[float64])
+		Expect(hist.DataPoints).To(HaveLen(1))
+		return hist.DataPoints[0].Exemplars
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should link HTTP request durations to the server span", func() {
+		newProvider()
+
+		var spanCtx trace.SpanContext
+		handler := gotel.NewHTTPMiddleware("exemplars-test", tracer, metrics).Handler(
+			http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				spanCtx = trace.SpanContextFromContext(r.Context())
+			}),
+		)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		exemplars := histogramExemplars("http_request_duration_seconds")
+		Expect(exemplars).To(HaveLen(1))
+		traceID, spanID := spanCtx.TraceID(), spanCtx.SpanID()
+		Expect(exemplars[0].TraceID).To(Equal(traceID[:]))
+		Expect(exemplars[0].SpanID).To(Equal(spanID[:]))
+	})
+
+	It("should link database query durations to the client span", func() {
+		newProvider()
+
+		parentCtx, parent := tracer.StartSpan(ctx, "parent")
+		defer parent.End()
+
+		db := gotel.NewDBTracer(tracer, metrics, "users", "postgresql")
+		Expect(db.Trace(parentCtx, "SELECT 1", func() error { return nil })).To(Succeed())
+
+		exemplars := histogramExemplars("db_query_duration_seconds")
+		Expect(exemplars).To(HaveLen(1))
+		traceID, parentSpanID := parent.Context().TraceID(), parent.Context().SpanID()
+		Expect(exemplars[0].TraceID).To(Equal(traceID[:]))
+		Expect(exemplars[0].SpanID).NotTo(Equal(parentSpanID[:]))
+	})
+
+	It("should not collect exemplars outside of a span with the trace-based filter", func() {
+		newProvider()
+
+		metrics.DBQueryDuration.Record(ctx, 0.1)
+		Expect(histogramExemplars("db_query_duration_seconds")).To(BeEmpty())
+	})
+
+	It("should size fixed reservoirs for non-histogram instruments", func() {
+		newProvider(
+			gotel.WithExemplarFilter(gotel.ExemplarFilterAlwaysOn),
+			gotel.WithExemplarReservoirSize(2),
+			gotel.WithMetricViews(gotel.MetricView{Instrument: "errors_total", AttributeKeys: []string{"type"}}),
+		)
+
+		for i := range 5 {
+			metrics.ErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("type", "timeout"),
+				attribute.Int("attempt", i),
+			))
+		}
+
+		sum := collectMetrics(ctx, reader)["errors_total"].Data.(metricdata.Sum[int64])
+		Expect(sum.DataPoints).To(HaveLen(1))
+		Expect(sum.DataPoints[0].Value).To(Equal(int64(5)))
+		Expect(sum.DataPoints[0].Attributes.ToSlice()).To(ConsistOf(attribute.String("type", "timeout")))
+
+		exemplars := sum.DataPoints[0].Exemplars
+		Expect(exemplars).To(HaveLen(2))
+		attempts := map[int64]bool{}
+		for _, ex := range exemplars {
+			Expect(ex.FilteredAttributes).To(HaveLen(1))
+			Expect(ex.FilteredAttributes[0].Key).To(Equal(attribute.Key("attempt")))
+			attempts[ex.FilteredAttributes[0].Value.AsInt64()] = true
+		}
+		Expect(attempts).To(HaveLen(2))
+	})
+})
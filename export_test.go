@@ -0,0 +1,31 @@
+package gotel
+
+import (
+	"runtime/metrics"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewZapLoggerWithCore returns a ZapLogger writing to core, so tests can
+// observe the entries it logs.
+func NewZapLoggerWithCore(core zapcore.Core) *ZapLogger {
+	return &ZapLogger{logger: zap.New(core)}
+}
+
+// IsRegistered reports whether the registry holds an entry for metricName.
+func IsRegistered(m *MetricRegistry, metricName string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.instruments[metricName]
+	return ok
+}
+
+// RuntimeDataPoint converts a runtime/metrics histogram with the given buckets
+// and counts into a data point, subtracting baseline.
+func RuntimeDataPoint(buckets []float64, counts, baseline []uint64) metricdata.HistogramDataPoint[float64] {
+	sample := &metrics.Float64Histogram{Buckets: buckets, Counts: counts}
+	return runtimeDataPoint(sample, baseline, time.Time{}, time.Time{})
+}
@@ -0,0 +1,270 @@
+package gotel
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// clockTicks is the kernel's USER_HZ, the unit of CPU times in /proc. It is
+// reported by sysconf(_SC_CLK_TCK), which Go cannot call without cgo, so it is
+// assumed to be 100. USER_HZ is part of the kernel ABI and independent of the
+// kernel's CONFIG_HZ; it is 100 on every architecture Go supports on Linux.
+const clockTicks = 100
+
+// hostCPUStates are the columns of the cpu line in /proc/stat, in order.
+var hostCPUStates = []string{"user", "nice", "system", "idle", "iowait", "irq", "softirq", "steal"}
+
+// HostMetrics reports process and host resource usage read from /proc.
+//
+// On platforms other than Linux, where /proc is not available, it registers no
+// instruments. Individual files that cannot be read are skipped, so a
+// restricted /proc still yields the metrics it can.
+type HostMetrics struct {
+	procRoot     string
+	registration *CallbackRegistration
+}
+
+// HostMetricsOption configures a HostMetrics.
+type HostMetricsOption func(*HostMetrics)
+
+// WithProcRoot reads from a /proc tree mounted at root, e.g. the host's /proc
+// mounted into a container, or a fake tree in tests.
+func WithProcRoot(root string) HostMetricsOption {
+	return func(h *HostMetrics) {
+		h.procRoot = root
+	}
+}
+
+// NewHostMetrics registers the process and host instruments on registry. Call
+// Stop to unregister them.
+func NewHostMetrics(registry *MetricRegistry, opts ...HostMetricsOption) (*HostMetrics, error) {
+	h := &HostMetrics{procRoot: "/proc"}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.procRoot == "/proc" && runtime.GOOS != "linux" {
+		return h, nil
+	}
+
+	processCPU, err := registry.FloatObservableCounter("process_cpu_seconds_total",
+		"Total user and system CPU time spent by the process", nil, metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	processRSS, err := registry.ObservableUpDownCounter("process_resident_memory_bytes",
+		"Resident memory size of the process", nil, metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	processFDs, err := registry.ObservableUpDownCounter("process_open_fds",
+		"Number of open file descriptors of the process", nil, metric.WithUnit("{file_descriptor}"))
+	if err != nil {
+		return nil, err
+	}
+	processThreads, err := registry.ObservableUpDownCounter("process_threads",
+		"Number of OS threads of the process", nil, metric.WithUnit("{thread}"))
+	if err != nil {
+		return nil, err
+	}
+	hostCPU, err := registry.FloatObservableCounter("host_cpu_seconds_total",
+		"Total CPU time spent by the host in each state", nil, metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	hostMemoryTotal, err := registry.ObservableUpDownCounter("host_memory_total_bytes",
+		"Total usable memory of the host", nil, metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	hostMemoryAvailable, err := registry.ObservableUpDownCounter("host_memory_available_bytes",
+		"Memory available to start new applications on the host", nil, metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	hostLoad, err := registry.FloatGauge("host_load_average",
+		"Host load average over 1, 5 and 15 minutes", nil, metric.WithUnit("{process}"))
+	if err != nil {
+		return nil, err
+	}
+
+	h.registration, err = registry.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if user, system, err := h.processCPU(); err == nil {
+			o.ObserveFloat64(processCPU, user, metric.WithAttributes(attribute.String("state", "user")))
+			o.ObserveFloat64(processCPU, system, metric.WithAttributes(attribute.String("state", "system")))
+		}
+		if status, err := h.readKeyValues("self/status"); err == nil {
+			if rss, ok := status["VmRSS"]; ok {
+				o.ObserveInt64(processRSS, rss)
+			}
+			if threads, ok := status["Threads"]; ok {
+				o.ObserveInt64(processThreads, threads)
+			}
+		}
+		if fds, err := os.ReadDir(filepath.Join(h.procRoot, "self", "fd")); err == nil {
+			o.ObserveInt64(processFDs, int64(len(fds)))
+		}
+		if states, err := h.hostCPU(); err == nil {
+			for i, seconds := range states {
+				o.ObserveFloat64(hostCPU, seconds, metric.WithAttributes(attribute.String("state", hostCPUStates[i])))
+			}
+		}
+		if meminfo, err := h.readKeyValues("meminfo"); err == nil {
+			if total, ok := meminfo["MemTotal"]; ok {
+				o.ObserveInt64(hostMemoryTotal, total)
+			}
+			if available, ok := meminfo["MemAvailable"]; ok {
+				o.ObserveInt64(hostMemoryAvailable, available)
+			}
+		}
+		if loads, err := h.loadAverage(); err == nil {
+			for i, window := range []string{"1m", "5m", "15m"} {
+				o.ObserveFloat64(hostLoad, loads[i], metric.WithAttributes(attribute.String("window", window)))
+			}
+		}
+		return nil
+	}, processCPU, processRSS, processFDs, processThreads, hostCPU, hostMemoryTotal, hostMemoryAvailable, hostLoad)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Stop unregisters the instruments. It is safe to call more than once.
+func (h *HostMetrics) Stop() error {
+	if h.registration == nil {
+		return nil
+	}
+	return h.registration.Unregister()
+}
+
+// processCPU returns the user and system CPU seconds from /proc/self/stat.
+func (h *HostMetrics) processCPU() (float64, float64, error) {
+	data, err := os.ReadFile(filepath.Join(h.procRoot, "self", "stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The command name may contain spaces and parentheses, so fields are
+	// counted from the last closing parenthesis, which precedes field 3.
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, 0, errors.New("malformed self/stat")
+	}
+
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return 0, 0, errors.New("malformed self/stat")
+	}
+
+	user, err := strconv.ParseFloat(fields[11], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed utime in self/stat: %w", err)
+	}
+	system, err := strconv.ParseFloat(fields[12], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed stime in self/stat: %w", err)
+	}
+	return user / clockTicks, system / clockTicks, nil
+}
+
+// hostCPU returns the seconds spent in each of hostCPUStates from /proc/stat.
+func (h *HostMetrics) hostCPU() ([]float64, error) {
+	file, err := os.Open(filepath.Join(h.procRoot, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "cpu" {
+			continue
+		}
+
+		states := make([]float64, 0, len(hostCPUStates))
+		for _, field := range fields[1:min(len(fields), len(hostCPUStates)+1)] {
+			ticks, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed cpu line in stat: %w", err)
+			}
+			states = append(states, ticks/clockTicks)
+		}
+		return states, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("no cpu line in stat")
+}
+
+// loadAverage returns the 1, 5 and 15 minute load averages from /proc/loadavg.
+func (h *HostMetrics) loadAverage() ([3]float64, error) {
+	var loads [3]float64
+
+	data, err := os.ReadFile(filepath.Join(h.procRoot, "loadavg"))
+	if err != nil {
+		return loads, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return loads, errors.New("malformed loadavg")
+	}
+
+	for i := range loads {
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return loads, fmt.Errorf("malformed loadavg: %w", err)
+		}
+	}
+	return loads, nil
+}
+
+// readKeyValues parses a "Key: value [kB]" file such as meminfo or
+// self/status. Values in kB are converted to bytes; lines whose value is not
+// an integer are skipped.
+func (h *HostMetrics) readKeyValues(name string) (map[string]int64, error) {
+	file, err := os.Open(filepath.Join(h.procRoot, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]int64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,144 @@
+package gotel_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+// writeFakeProc lays out a minimal /proc tree under root.
+func writeFakeProc(root string) {
+	files := map[string]string{
+		"self/stat": "4242 (my (odd) app) S 1 4242 4242 0 -1 4194560 100 0 0 0 " +
+			"250 50 0 0 20 0 7 0 1000 123456 789 18446744073709551615\n",
+		"self/status": "Name:\tmy app\nState:\tS (sleeping)\nVmRSS:\t    2048 kB\nThreads:\t7\n",
+		"stat":        "cpu  1000 20 300 5000 40 0 10 0 0 0\ncpu0 500 10 150 2500 20 0 5 0 0 0\n",
+		"meminfo":     "MemTotal:       16384 kB\nMemFree:         1024 kB\nMemAvailable:    8192 kB\n",
+		"loadavg":     "0.50 0.25 0.10 1/234 4242\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	fds := filepath.Join(root, "self", "fd")
+	Expect(os.MkdirAll(fds, 0o755)).To(Succeed())
+	for _, fd := range []string{"0", "1", "2"} {
+		Expect(os.WriteFile(filepath.Join(fds, fd), nil, 0o644)).To(Succeed())
+	}
+}
+
+var _ = Describe("HostMetrics", func() {
+	var (
+		ctx      context.Context
+		reader   *sdkmetric.ManualReader
+		registry *gotel.MetricRegistry
+		root     string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+		meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("host-test")
+		registry = gotel.NewMetricRegistry(meter, "app")
+		root = GinkgoT().TempDir()
+	})
+
+	// pointValue returns the value of the data point with the given attribute.
+	pointValue := func(points []metricdata.DataPoint[float64], key, value string) float64 {
+		for _, point := range points {
+			if v, ok := point.Attributes.Value(attribute.Key(key)); ok && v.AsString() == value {
+				return point.Value
+			}
+		}
+		Fail("no data point with " + key + "=" + value)
+		return 0
+	}
+
+	It("should report process and host usage from the proc tree", func() {
+		writeFakeProc(root)
+		host, err := gotel.NewHostMetrics(registry, gotel.WithProcRoot(root))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(host.Stop)
+
+		metrics := collectMetrics(ctx, reader)
+
+		processCPU := metrics["app_process_cpu_seconds_total"].Data.(metricdata.Sum[float64]).DataPoints
+		Expect(pointValue(processCPU, "state", "user")).To(Equal(2.5))
+		Expect(pointValue(processCPU, "state", "system")).To(Equal(0.5))
+
+		Expect(metrics["app_process_resident_memory_bytes"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).
+			To(Equal(int64(2048 * 1024)))
+		Expect(metrics["app_process_threads"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(Equal(int64(7)))
+		Expect(metrics["app_process_open_fds"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(Equal(int64(3)))
+
+		hostCPU := metrics["app_host_cpu_seconds_total"].Data.(metricdata.Sum[float64]).DataPoints
+		Expect(hostCPU).To(HaveLen(8))
+		Expect(pointValue(hostCPU, "state", "idle")).To(Equal(50.0))
+		Expect(pointValue(hostCPU, "state", "softirq")).To(Equal(0.1))
+
+		Expect(metrics["app_host_memory_total_bytes"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).
+			To(Equal(int64(16384 * 1024)))
+		Expect(metrics["app_host_memory_available_bytes"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).
+			To(Equal(int64(8192 * 1024)))
+
+		load := metrics["app_host_load_average"].Data.(metricdata.Gauge[float64]).DataPoints
+		Expect(pointValue(load, "window", "1m")).To(Equal(0.5))
+		Expect(pointValue(load, "window", "15m")).To(Equal(0.1))
+	})
+
+	DescribeTable("naming host metrics under each unit suffix rule",
+		func(rule gotel.UnitSuffixRule, expected ...string) {
+			reader = sdkmetric.NewManualReader()
+			meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("host-test")
+			registry = gotel.NewMetricRegistry(meter, "app", gotel.WithNamingPolicy(gotel.NamingPolicy{UnitSuffix: rule}))
+
+			writeFakeProc(root)
+			host, err := gotel.NewHostMetrics(registry, gotel.WithProcRoot(root))
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(host.Stop)
+
+			metrics := collectMetrics(ctx, reader)
+			for _, name := range expected {
+				Expect(metrics).To(HaveKey(name))
+			}
+		},
+		Entry("keep", gotel.UnitSuffixKeep,
+			"app_process_cpu_seconds_total", "app_host_cpu_seconds_total", "app_process_resident_memory_bytes"),
+		Entry("append", gotel.UnitSuffixAppend,
+			"app_process_cpu_seconds_total", "app_host_cpu_seconds_total", "app_process_resident_memory_bytes"),
+		Entry("strip", gotel.UnitSuffixStrip,
+			"app_process_cpu_total", "app_host_cpu_total", "app_process_resident_memory"),
+	)
+
+	It("should skip metrics whose files are missing", func() {
+		Expect(os.WriteFile(filepath.Join(root, "loadavg"), []byte("1.00 2.00 3.00 1/1 1\n"), 0o644)).To(Succeed())
+		host, err := gotel.NewHostMetrics(registry, gotel.WithProcRoot(root))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(host.Stop)
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics).To(HaveKey("app_host_load_average"))
+		Expect(metrics).NotTo(HaveKey("app_process_cpu_seconds_total"))
+		Expect(metrics).NotTo(HaveKey("app_host_memory_total_bytes"))
+	})
+
+	It("should stop reporting once stopped", func() {
+		writeFakeProc(root)
+		host, err := gotel.NewHostMetrics(registry, gotel.WithProcRoot(root))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(host.Stop()).To(Succeed())
+		Expect(host.Stop()).To(Succeed())
+		Expect(collectMetrics(ctx, reader)).NotTo(HaveKey("app_host_load_average"))
+	})
+})
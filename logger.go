@@ -65,22 +65,22 @@ func (l *ZapLogger) WithContext(ctx context.Context) *zap.Logger {
 
 // Info logs an info level message and attaches trace/span info.
 func (l *ZapLogger) Info(ctx context.Context, msg string, fields ...zap.Field) {
-	l.WithContext(ctx).Info(msg, fields...)
+	l.WithContext(ctx).Info(msg, expandErrorFields(fields)...)
 }
 
 // Error logs an error level message and attaches trace/span info.
 func (l *ZapLogger) Error(ctx context.Context, msg string, fields ...zap.Field) {
-	l.WithContext(ctx).Error(msg, fields...)
+	l.WithContext(ctx).Error(msg, expandErrorFields(fields)...)
 }
 
 // Warn logs a warning level message and attaches trace/span info.
 func (l *ZapLogger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
-	l.WithContext(ctx).Warn(msg, fields...)
+	l.WithContext(ctx).Warn(msg, expandErrorFields(fields)...)
 }
 
 // Debug logs a debug level message and attaches trace/span info.
 func (l *ZapLogger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
-	l.WithContext(ctx).Debug(msg, fields...)
+	l.WithContext(ctx).Debug(msg, expandErrorFields(fields)...)
 }
 
 // Sync flushes any buffered log entries.
@@ -2,135 +2,507 @@ package gotel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
+// ErrInstrumentKindConflict is returned when a metric name is requested as a
+// different kind of instrument than the one it was first registered as.
+var ErrInstrumentKindConflict = errors.New("metric already registered as a different instrument kind")
+
+// instrumentKind names the type of instrument stored under a metric name.
+type instrumentKind string
+
+const (
+	kindInt64Counter                   instrumentKind = "Int64Counter"
+	kindFloat64Counter                 instrumentKind = "Float64Counter"
+	kindInt64UpDownCounter             instrumentKind = "Int64UpDownCounter"
+	kindFloat64UpDownCounter           instrumentKind = "Float64UpDownCounter"
+	kindInt64Histogram                 instrumentKind = "Int64Histogram"
+	kindFloat64Histogram               instrumentKind = "Float64Histogram"
+	kindInt64Gauge                     instrumentKind = "Int64Gauge"
+	kindFloat64Gauge                   instrumentKind = "Float64Gauge"
+	kindInt64ObservableCounter         instrumentKind = "Int64ObservableCounter"
+	kindFloat64ObservableCounter       instrumentKind = "Float64ObservableCounter"
+	kindInt64ObservableUpDownCounter   instrumentKind = "Int64ObservableUpDownCounter"
+	kindFloat64ObservableUpDownCounter instrumentKind = "Float64ObservableUpDownCounter"
+	kindInt64ObservableGauge           instrumentKind = "Int64ObservableGauge"
+	kindFloat64ObservableGauge         instrumentKind = "Float64ObservableGauge"
+)
+
+// sdkKind returns the SDK instrument kind, used to look up its temporality.
+func (k instrumentKind) sdkKind() sdkmetric.InstrumentKind {
+	switch k {
+	case kindInt64Counter, kindFloat64Counter:
+		return sdkmetric.InstrumentKindCounter
+	case kindInt64UpDownCounter, kindFloat64UpDownCounter:
+		return sdkmetric.InstrumentKindUpDownCounter
+	case kindInt64Histogram, kindFloat64Histogram:
+		return sdkmetric.InstrumentKindHistogram
+	case kindInt64Gauge, kindFloat64Gauge:
+		return sdkmetric.InstrumentKindGauge
+	case kindInt64ObservableCounter, kindFloat64ObservableCounter:
+		return sdkmetric.InstrumentKindObservableCounter
+	case kindInt64ObservableUpDownCounter, kindFloat64ObservableUpDownCounter:
+		return sdkmetric.InstrumentKindObservableUpDownCounter
+	default:
+		return sdkmetric.InstrumentKindObservableGauge
+	}
+}
+
+// registeredInstrument is an instrument cached by the registry together with
+// the name it was requested under and its kind.
+type registeredInstrument struct {
+	name       string
+	kind       instrumentKind
+	instrument any
+}
+
 // MetricRegistry provides a convenient way to create and manage custom metrics.
 type MetricRegistry struct {
-	prefix         string
-	meter          metric.Meter
-	counters       map[string]metric.Int64Counter
-	histograms     map[string]metric.Float64Histogram
-	upDownCounters map[string]metric.Int64UpDownCounter
-	gauges         map[string]metric.Int64ObservableGauge
-	mu             sync.RWMutex
+	prefix      string
+	meter       metric.Meter
+	logger      *ZapLogger
+	naming      NamingPolicy
+	instruments map[string]registeredInstrument
+	forgotten   map[string]instrumentKind
+	mu          sync.RWMutex
+
+	cardinalityLimit  int
+	cardinalityLimits map[string]int
+	overflowCounter   metric.Int64Counter
+	temporality       sdkmetric.TemporalitySelector
+	deltaLimiters     []*cardinalityLimiter
+	limitersMu        sync.Mutex
 }
 
+// RegistryOption configures a MetricRegistry.
+type RegistryOption func(*MetricRegistry)
+
 // NewMetricRegistry creates a new MetricRegistry.
-func NewMetricRegistry(meter metric.Meter, prefix string) *MetricRegistry {
-	return &MetricRegistry{
-		meter:          meter,
-		prefix:         prefix,
-		counters:       make(map[string]metric.Int64Counter),
-		histograms:     make(map[string]metric.Float64Histogram),
-		upDownCounters: make(map[string]metric.Int64UpDownCounter),
-		gauges:         make(map[string]metric.Int64ObservableGauge),
+func NewMetricRegistry(meter metric.Meter, prefix string, opts ...RegistryOption) *MetricRegistry {
+	m := &MetricRegistry{
+		meter:             meter,
+		prefix:            prefix,
+		instruments:       make(map[string]registeredInstrument),
+		forgotten:         make(map[string]instrumentKind),
+		cardinalityLimit:  DefaultCardinalityLimit,
+		cardinalityLimits: make(map[string]int),
 	}
-}
 
-// Counter creates or returns an existing counter metric.
-func (m *MetricRegistry) Counter(
-	name, description string, options ...metric.Int64CounterOption,
-) (metric.Int64Counter, error) {
-	metricName := m.generateMetricName(name)
+	for _, opt := range opts {
+		opt(m)
+	}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.initOverflowCounter()
+	m.initCardinalityReset()
+	return m
+}
 
-	if counter, exists := m.counters[metricName]; exists {
-		return counter, nil
+// WithCardinalityLimit sets the default number of distinct attribute sets each
+// instrument may record. A limit of zero or less disables the check. Sets are
+// counted over the registry's lifetime unless WithCardinalityReset applies.
+func WithCardinalityLimit(limit int) RegistryOption {
+	return func(m *MetricRegistry) {
+		m.cardinalityLimit = limit
 	}
+}
 
-	options = append(options, metric.WithDescription(description))
-	counter, err := m.meter.Int64Counter(metricName, options...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create counter %s: %w", metricName, err)
+// WithMetricCardinalityLimit overrides the cardinality limit for a single
+// instrument, identified by the name passed to the registry (without prefix).
+func WithMetricCardinalityLimit(name string, limit int) RegistryOption {
+	return func(m *MetricRegistry) {
+		m.cardinalityLimits[name] = limit
+	}
+}
+
+// WithRegistryLogger sets the logger used to warn about registry problems,
+// such as an instrument exceeding its cardinality limit.
+func WithRegistryLogger(logger *ZapLogger) RegistryOption {
+	return func(m *MetricRegistry) {
+		m.logger = logger
 	}
+}
 
-	m.counters[metricName] = counter
-	return counter, nil
+// kindConflict returns the error for metricName being requested as another kind.
+func kindConflict(metricName string, existing, requested instrumentKind) error {
+	return fmt.Errorf("%w: %s is a %s, requested as %s", ErrInstrumentKindConflict, metricName, existing, requested)
 }
 
-// Histogram creates or returns an existing histogram metric.
-func (m *MetricRegistry) Histogram(name, description string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
-	metricName := m.generateMetricName(name)
+// getOrCreate returns the instrument cached under the generated name, or builds
+// and caches it with create. Requesting a cached name as another kind fails
+// with ErrInstrumentKindConflict.
+func getOrCreate[T any](
+	m *MetricRegistry, name, unit string, kind instrumentKind, create func(metricName string) (T, error),
+) (T, error) {
+	var zero T
+	metricName, err := m.generateMetricName(name, unit)
+	if err != nil {
+		return zero, err
+	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if histogram, exists := m.histograms[metricName]; exists {
-		return histogram, nil
+	if existing, exists := m.instruments[metricName]; exists {
+		if existing.kind != kind {
+			return zero, kindConflict(metricName, existing.kind, kind)
+		}
+		return existing.instrument.(T), nil
 	}
 
-	options = append(options, metric.WithDescription(description))
-	histogram, err := m.meter.Float64Histogram(metricName, options...)
+	// The SDK meter keeps forgotten instruments, so their names stay bound to
+	// their kind.
+	if previous, forgotten := m.forgotten[metricName]; forgotten && previous != kind {
+		return zero, kindConflict(metricName, previous, kind)
+	}
+
+	instrument, err := create(metricName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create histogram %s: %w", metricName, err)
+		return zero, err
 	}
 
-	m.histograms[metricName] = histogram
-	return histogram, nil
+	delete(m.forgotten, metricName)
+	m.instruments[metricName] = registeredInstrument{name: name, kind: kind, instrument: instrument}
+	return instrument, nil
 }
 
-// UpDownCounter creates or returns an existing up/down counter metric.
-func (m *MetricRegistry) UpDownCounter(name, description string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
-	metricName := m.generateMetricName(name)
+// Counter creates or returns an existing counter metric.
+func (m *MetricRegistry) Counter(
+	name, description string, options ...metric.Int64CounterOption,
+) (*Counter, error) {
+	unit := metric.NewInt64CounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64Counter,
+		func(metricName string) (*Counter, error) {
+			options = append(options, metric.WithDescription(description))
+			counter, err := m.meter.Int64Counter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create counter %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindInt64Counter); limiter != nil {
+				counter = &limitedInt64Counter{Int64Counter: counter, limiter: limiter}
+			}
+			return &Counter{Int64Counter: counter}, nil
+		},
+	)
+}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// FloatCounter creates or returns an existing float counter metric, for
+// monotonic quantities such as bytes or money.
+func (m *MetricRegistry) FloatCounter(
+	name, description string, options ...metric.Float64CounterOption,
+) (*FloatCounter, error) {
+	unit := metric.NewFloat64CounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64Counter,
+		func(metricName string) (*FloatCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			counter, err := m.meter.Float64Counter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create float counter %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindFloat64Counter); limiter != nil {
+				counter = &limitedFloat64Counter{Float64Counter: counter, limiter: limiter}
+			}
+			return &FloatCounter{Float64Counter: counter}, nil
+		},
+	)
+}
 
-	if upDownCounter, exists := m.upDownCounters[metricName]; exists {
-		return upDownCounter, nil
-	}
+// Histogram creates or returns an existing histogram metric.
+func (m *MetricRegistry) Histogram(
+	name, description string, options ...metric.Float64HistogramOption,
+) (*Histogram, error) {
+	unit := metric.NewFloat64HistogramConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64Histogram,
+		func(metricName string) (*Histogram, error) {
+			options = append(options, metric.WithDescription(description))
+			histogram, err := m.meter.Float64Histogram(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create histogram %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindFloat64Histogram); limiter != nil {
+				histogram = &limitedFloat64Histogram{Float64Histogram: histogram, limiter: limiter}
+			}
+			return &Histogram{Float64Histogram: histogram}, nil
+		},
+	)
+}
 
-	options = append(options, metric.WithDescription(description))
-	upDownCounter, err := m.meter.Int64UpDownCounter(metricName, options...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create up/down counter %s: %w", metricName, err)
-	}
+// IntHistogram creates or returns an existing integer histogram metric.
+func (m *MetricRegistry) IntHistogram(
+	name, description string, options ...metric.Int64HistogramOption,
+) (*IntHistogram, error) {
+	unit := metric.NewInt64HistogramConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64Histogram,
+		func(metricName string) (*IntHistogram, error) {
+			options = append(options, metric.WithDescription(description))
+			histogram, err := m.meter.Int64Histogram(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create int histogram %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindInt64Histogram); limiter != nil {
+				histogram = &limitedInt64Histogram{Int64Histogram: histogram, limiter: limiter}
+			}
+			return &IntHistogram{Int64Histogram: histogram}, nil
+		},
+	)
+}
+
+// UpDownCounter creates or returns an existing up/down counter metric.
+func (m *MetricRegistry) UpDownCounter(
+	name, description string, options ...metric.Int64UpDownCounterOption,
+) (*UpDownCounter, error) {
+	unit := metric.NewInt64UpDownCounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64UpDownCounter,
+		func(metricName string) (*UpDownCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			upDownCounter, err := m.meter.Int64UpDownCounter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create up/down counter %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindInt64UpDownCounter); limiter != nil {
+				upDownCounter = &limitedInt64UpDownCounter{Int64UpDownCounter: upDownCounter, limiter: limiter}
+			}
+			return &UpDownCounter{Int64UpDownCounter: upDownCounter}, nil
+		},
+	)
+}
+
+// FloatUpDownCounter creates or returns an existing float up/down counter metric.
+func (m *MetricRegistry) FloatUpDownCounter(
+	name, description string, options ...metric.Float64UpDownCounterOption,
+) (*FloatUpDownCounter, error) {
+	unit := metric.NewFloat64UpDownCounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64UpDownCounter,
+		func(metricName string) (*FloatUpDownCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			upDownCounter, err := m.meter.Float64UpDownCounter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create float up/down counter %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindFloat64UpDownCounter); limiter != nil {
+				upDownCounter = &limitedFloat64UpDownCounter{Float64UpDownCounter: upDownCounter, limiter: limiter}
+			}
+			return &FloatUpDownCounter{Float64UpDownCounter: upDownCounter}, nil
+		},
+	)
+}
+
+// SyncGauge creates or returns an existing synchronous gauge metric, whose
+// current value is recorded directly instead of being observed in a callback.
+func (m *MetricRegistry) SyncGauge(
+	name, description string, options ...metric.Int64GaugeOption,
+) (*SyncGauge, error) {
+	unit := metric.NewInt64GaugeConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64Gauge,
+		func(metricName string) (*SyncGauge, error) {
+			options = append(options, metric.WithDescription(description))
+			gauge, err := m.meter.Int64Gauge(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create sync gauge %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindInt64Gauge); limiter != nil {
+				gauge = &limitedInt64Gauge{Int64Gauge: gauge, limiter: limiter}
+			}
+			return &SyncGauge{Int64Gauge: gauge}, nil
+		},
+	)
+}
 
-	m.upDownCounters[metricName] = upDownCounter
-	return upDownCounter, nil
+// FloatSyncGauge creates or returns an existing synchronous float gauge metric.
+func (m *MetricRegistry) FloatSyncGauge(
+	name, description string, options ...metric.Float64GaugeOption,
+) (*FloatSyncGauge, error) {
+	unit := metric.NewFloat64GaugeConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64Gauge,
+		func(metricName string) (*FloatSyncGauge, error) {
+			options = append(options, metric.WithDescription(description))
+			gauge, err := m.meter.Float64Gauge(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create float sync gauge %s: %w", metricName, err)
+			}
+
+			if limiter := m.newCardinalityLimiter(name, metricName, kindFloat64Gauge); limiter != nil {
+				gauge = &limitedFloat64Gauge{Float64Gauge: gauge, limiter: limiter}
+			}
+			return &FloatSyncGauge{Float64Gauge: gauge}, nil
+		},
+	)
 }
 
 // Gauge creates or returns an existing observable gauge metric.
+// A nil callback creates the gauge without binding a callback to it.
 func (m *MetricRegistry) Gauge(
 	name string,
 	description string,
 	callback func(context.Context, metric.Int64Observer) error,
 	options ...metric.Int64ObservableGaugeOption,
 ) (metric.Int64ObservableGauge, error) {
-	metricName := m.generateMetricName(name)
+	unit := metric.NewInt64ObservableGaugeConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64ObservableGauge,
+		func(metricName string) (metric.Int64ObservableGauge, error) {
+			options = append(options, metric.WithDescription(description))
+			if callback != nil {
+				limited := m.limitInt64Callback(name, metricName, kindInt64ObservableGauge, callback)
+				options = append(options, metric.WithInt64Callback(limited))
+			}
+
+			gauge, err := m.meter.Int64ObservableGauge(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create gauge %s: %w", metricName, err)
+			}
+			return gauge, nil
+		},
+	)
+}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// FloatGauge creates or returns an existing observable float gauge metric.
+// A nil callback creates the gauge without binding a callback to it.
+func (m *MetricRegistry) FloatGauge(
+	name string,
+	description string,
+	callback func(context.Context, metric.Float64Observer) error,
+	options ...metric.Float64ObservableGaugeOption,
+) (metric.Float64ObservableGauge, error) {
+	unit := metric.NewFloat64ObservableGaugeConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64ObservableGauge,
+		func(metricName string) (metric.Float64ObservableGauge, error) {
+			options = append(options, metric.WithDescription(description))
+			if callback != nil {
+				limited := m.limitFloat64Callback(name, metricName, kindFloat64ObservableGauge, callback)
+				options = append(options, metric.WithFloat64Callback(limited))
+			}
+
+			gauge, err := m.meter.Float64ObservableGauge(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create float gauge %s: %w", metricName, err)
+			}
+			return gauge, nil
+		},
+	)
+}
 
-	if gauge, exists := m.gauges[metricName]; exists {
-		return gauge, nil
-	}
+// ObservableCounter creates or returns an existing observable counter metric,
+// for monotonic totals read from another source such as a connection pool.
+// A nil callback creates the counter without binding a callback to it.
+func (m *MetricRegistry) ObservableCounter(
+	name string,
+	description string,
+	callback func(context.Context, metric.Int64Observer) error,
+	options ...metric.Int64ObservableCounterOption,
+) (metric.Int64ObservableCounter, error) {
+	unit := metric.NewInt64ObservableCounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64ObservableCounter,
+		func(metricName string) (metric.Int64ObservableCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			if callback != nil {
+				limited := m.limitInt64Callback(name, metricName, kindInt64ObservableCounter, callback)
+				options = append(options, metric.WithInt64Callback(limited))
+			}
+
+			counter, err := m.meter.Int64ObservableCounter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create observable counter %s: %w", metricName, err)
+			}
+			return counter, nil
+		},
+	)
+}
 
-	options = append(options, metric.WithDescription(description), metric.WithInt64Callback(callback))
-	gauge, err := m.meter.Int64ObservableGauge(metricName, options...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gauge %s: %w", metricName, err)
-	}
+// FloatObservableCounter creates or returns an existing observable float counter metric.
+// A nil callback creates the counter without binding a callback to it.
+func (m *MetricRegistry) FloatObservableCounter(
+	name string,
+	description string,
+	callback func(context.Context, metric.Float64Observer) error,
+	options ...metric.Float64ObservableCounterOption,
+) (metric.Float64ObservableCounter, error) {
+	unit := metric.NewFloat64ObservableCounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64ObservableCounter,
+		func(metricName string) (metric.Float64ObservableCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			if callback != nil {
+				limited := m.limitFloat64Callback(name, metricName, kindFloat64ObservableCounter, callback)
+				options = append(options, metric.WithFloat64Callback(limited))
+			}
+
+			counter, err := m.meter.Float64ObservableCounter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create float observable counter %s: %w", metricName, err)
+			}
+			return counter, nil
+		},
+	)
+}
 
-	m.gauges[metricName] = gauge
-	return gauge, nil
+// ObservableUpDownCounter creates or returns an existing observable up/down counter metric.
+// A nil callback creates the counter without binding a callback to it.
+func (m *MetricRegistry) ObservableUpDownCounter(
+	name string,
+	description string,
+	callback func(context.Context, metric.Int64Observer) error,
+	options ...metric.Int64ObservableUpDownCounterOption,
+) (metric.Int64ObservableUpDownCounter, error) {
+	unit := metric.NewInt64ObservableUpDownCounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindInt64ObservableUpDownCounter,
+		func(metricName string) (metric.Int64ObservableUpDownCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			if callback != nil {
+				limited := m.limitInt64Callback(name, metricName, kindInt64ObservableUpDownCounter, callback)
+				options = append(options, metric.WithInt64Callback(limited))
+			}
+
+			upDownCounter, err := m.meter.Int64ObservableUpDownCounter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create observable up/down counter %s: %w", metricName, err)
+			}
+			return upDownCounter, nil
+		},
+	)
 }
 
-func (m *MetricRegistry) generateMetricName(name string) string {
-	if m.prefix == "" {
-		return name
-	}
-	return fmt.Sprintf("%s_%s", m.prefix, name)
+// FloatObservableUpDownCounter creates or returns an existing observable float up/down counter metric.
+// A nil callback creates the counter without binding a callback to it.
+func (m *MetricRegistry) FloatObservableUpDownCounter(
+	name string,
+	description string,
+	callback func(context.Context, metric.Float64Observer) error,
+	options ...metric.Float64ObservableUpDownCounterOption,
+) (metric.Float64ObservableUpDownCounter, error) {
+	unit := metric.NewFloat64ObservableUpDownCounterConfig(options...).Unit()
+	return getOrCreate(m, name, unit, kindFloat64ObservableUpDownCounter,
+		func(metricName string) (metric.Float64ObservableUpDownCounter, error) {
+			options = append(options, metric.WithDescription(description))
+			if callback != nil {
+				limited := m.limitFloat64Callback(name, metricName, kindFloat64ObservableUpDownCounter, callback)
+				options = append(options, metric.WithFloat64Callback(limited))
+			}
+
+			upDownCounter, err := m.meter.Float64ObservableUpDownCounter(metricName, options...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create float observable up/down counter %s: %w", metricName, err)
+			}
+			return upDownCounter, nil
+		},
+	)
 }
 
 // CommonMetrics provides a set of commonly used metrics for web applications.
+//
+// Which HTTP and database metrics are populated depends on the
+// MetricConvention; the fields of the convention that is not emitted are nil.
 type CommonMetrics struct {
 	registry            *MetricRegistry
 	HTTPRequestsTotal   metric.Int64Counter
@@ -141,47 +513,37 @@ type CommonMetrics struct {
 	DBQueryDuration     metric.Float64Histogram
 	ErrorsTotal         metric.Int64Counter
 	StartTime           metric.Int64ObservableGauge
-}
 
-// NewCommonMetrics creates a new set of common metrics.
-func NewCommonMetrics(registry *MetricRegistry) (*CommonMetrics, error) {
-	cm := &CommonMetrics{registry: registry}
-	var err error
+	HTTPServerRequestDuration metric.Float64Histogram
+	HTTPServerActiveRequests  metric.Int64UpDownCounter
+	DBClientOperationDuration metric.Float64Histogram
 
-	cm.HTTPRequestsTotal, err = registry.Counter("http_requests_total", "Total number of HTTP requests")
-	if err != nil {
-		return nil, err
-	}
+	convention MetricConvention
+}
 
-	cm.HTTPRequestDuration, err = registry.Histogram(
-		"http_request_duration_seconds",
-		"Duration of HTTP requests in seconds",
-		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
-	)
-	if err != nil {
-		return nil, err
+// NewCommonMetrics creates a new set of common metrics.
+func NewCommonMetrics(registry *MetricRegistry, opts ...CommonMetricsOption) (*CommonMetrics, error) {
+	cfg := &commonMetricsConfig{convention: ConventionLegacy}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	cm.HTTPActiveRequests, err = registry.UpDownCounter("http_active_requests", "Number of active HTTP requests")
-	if err != nil {
-		return nil, err
-	}
+	cm := &CommonMetrics{registry: registry, convention: cfg.convention}
+	var err error
 
-	cm.DBConnectionsActive, err = registry.UpDownCounter("db_connections_active", "Number of active database connections")
-	if err != nil {
-		return nil, err
+	if cm.convention.legacy() {
+		if err = cm.initLegacyMetrics(registry); err != nil {
+			return nil, err
+		}
 	}
 
-	cm.DBQueriesTotal, err = registry.Counter("db_queries_total", "Total number of database queries")
-	if err != nil {
-		return nil, err
+	if cm.convention.semconv() {
+		if err = cm.initSemconvMetrics(registry.unprefixed()); err != nil {
+			return nil, err
+		}
 	}
 
-	cm.DBQueryDuration, err = registry.Histogram(
-		"db_query_duration_seconds",
-		"Duration of database queries in seconds",
-		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1),
-	)
+	cm.DBConnectionsActive, err = registry.UpDownCounter("db_connections_active", "Number of active database connections")
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +555,7 @@ func NewCommonMetrics(registry *MetricRegistry) (*CommonMetrics, error) {
 
 	startTime := time.Now().Unix()
 	cm.StartTime, err = registry.Gauge(
-		registry.generateMetricName("start_time_seconds"),
+		"start_time_seconds",
 		"Unix timestamp of when the application started",
 		func(_ context.Context, o metric.Int64Observer) error {
 			o.Observe(startTime)
@@ -206,3 +568,39 @@ func NewCommonMetrics(registry *MetricRegistry) (*CommonMetrics, error) {
 
 	return cm, nil
 }
+
+// initLegacyMetrics creates the Prometheus-style HTTP and database metrics.
+func (cm *CommonMetrics) initLegacyMetrics(registry *MetricRegistry) error {
+	var err error
+
+	cm.HTTPRequestsTotal, err = registry.Counter("http_requests_total", "Total number of HTTP requests")
+	if err != nil {
+		return err
+	}
+
+	cm.HTTPRequestDuration, err = registry.Histogram(
+		"http_request_duration_seconds",
+		"Duration of HTTP requests in seconds",
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+	)
+	if err != nil {
+		return err
+	}
+
+	cm.HTTPActiveRequests, err = registry.UpDownCounter("http_active_requests", "Number of active HTTP requests")
+	if err != nil {
+		return err
+	}
+
+	cm.DBQueriesTotal, err = registry.Counter("db_queries_total", "Total number of database queries")
+	if err != nil {
+		return err
+	}
+
+	cm.DBQueryDuration, err = registry.Histogram(
+		"db_query_duration_seconds",
+		"Duration of database queries in seconds",
+		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1),
+	)
+	return err
+}
@@ -0,0 +1,221 @@
+package gotel_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("MetricRegistry", func() {
+	var (
+		ctx      context.Context
+		reader   *sdkmetric.ManualReader
+		registry *gotel.MetricRegistry
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+		meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("registry-test")
+		registry = gotel.NewMetricRegistry(meter, "app")
+	})
+
+	Context("get-or-create caching", func() {
+		It("should return the same instrument for repeated requests", func() {
+			first, err := registry.FloatCounter("bytes_total", "Total bytes")
+			Expect(err).NotTo(HaveOccurred())
+			second, err := registry.FloatCounter("bytes_total", "Total bytes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(BeIdenticalTo(first))
+		})
+
+		It("should reject a name requested as a different instrument kind", func() {
+			_, err := registry.Counter("jobs", "Jobs")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = registry.FloatCounter("jobs", "Jobs")
+			Expect(errors.Is(err, gotel.ErrInstrumentKindConflict)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("app_jobs is a Int64Counter, requested as Float64Counter")))
+		})
+	})
+
+	Context("instrument coverage", func() {
+		It("should create prefixed synchronous instruments of every kind", func() {
+			floatCounter, err := registry.FloatCounter("revenue_total", "Revenue")
+			Expect(err).NotTo(HaveOccurred())
+			floatCounter.Add(ctx, 9.99)
+
+			intHistogram, err := registry.IntHistogram("payload_bytes", "Payload size")
+			Expect(err).NotTo(HaveOccurred())
+			intHistogram.Record(ctx, 512)
+
+			floatUpDown, err := registry.FloatUpDownCounter("balance", "Balance")
+			Expect(err).NotTo(HaveOccurred())
+			floatUpDown.Add(ctx, -2.5)
+
+			intGauge, err := registry.SyncGauge("workers", "Workers")
+			Expect(err).NotTo(HaveOccurred())
+			intGauge.Record(ctx, 4)
+
+			floatGauge, err := registry.FloatSyncGauge("temperature", "Temperature")
+			Expect(err).NotTo(HaveOccurred())
+			floatGauge.Record(ctx, 21.5)
+
+			metrics := collectMetrics(ctx, reader)
+			Expect(metrics["app_revenue_total"].Data.(metricdata.Sum[float64]).DataPoints[0].Value).To(Equal(9.99))
+			Expect(metrics["app_payload_bytes"].Data.(metricdata.Histogram[int64]).DataPoints[0].Sum).To(Equal(int64(512)))
+			Expect(metrics["app_balance"].Data.(metricdata.Sum[float64]).DataPoints[0].Value).To(Equal(-2.5))
+			Expect(metrics["app_workers"].Data.(metricdata.Gauge[int64]).DataPoints[0].Value).To(Equal(int64(4)))
+			Expect(metrics["app_temperature"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value).To(Equal(21.5))
+		})
+
+		It("should create prefixed observable instruments of every kind", func() {
+			_, err := registry.FloatGauge("load", "Load", func(_ context.Context, o metric.Float64Observer) error {
+				o.Observe(0.75)
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = registry.ObservableCounter("pool_acquired_total", "Acquired connections",
+				func(_ context.Context, o metric.Int64Observer) error {
+					o.Observe(10)
+					return nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = registry.FloatObservableCounter("cpu_seconds_total", "CPU time",
+				func(_ context.Context, o metric.Float64Observer) error {
+					o.Observe(1.5)
+					return nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = registry.ObservableUpDownCounter("pool_idle", "Idle connections",
+				func(_ context.Context, o metric.Int64Observer) error {
+					o.Observe(3)
+					return nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = registry.FloatObservableUpDownCounter("queue_weight", "Queue weight",
+				func(_ context.Context, o metric.Float64Observer) error {
+					o.Observe(2.25)
+					return nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			metrics := collectMetrics(ctx, reader)
+			Expect(metrics["app_load"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value).To(Equal(0.75))
+
+			acquired := metrics["app_pool_acquired_total"].Data.(metricdata.Sum[int64])
+			Expect(acquired.IsMonotonic).To(BeTrue())
+			Expect(acquired.DataPoints[0].Value).To(Equal(int64(10)))
+
+			cpu := metrics["app_cpu_seconds_total"].Data.(metricdata.Sum[float64])
+			Expect(cpu.IsMonotonic).To(BeTrue())
+			Expect(cpu.DataPoints[0].Value).To(Equal(1.5))
+
+			idle := metrics["app_pool_idle"].Data.(metricdata.Sum[int64])
+			Expect(idle.IsMonotonic).To(BeFalse())
+			Expect(idle.DataPoints[0].Value).To(Equal(int64(3)))
+
+			weight := metrics["app_queue_weight"].Data.(metricdata.Sum[float64])
+			Expect(weight.IsMonotonic).To(BeFalse())
+			Expect(weight.DataPoints[0].Value).To(Equal(2.25))
+		})
+	})
+
+	Context("RegisterCallback", func() {
+		It("should observe several instruments from one callback until unregistered", func() {
+			idle, err := registry.ObservableUpDownCounter("pool_idle", "Idle connections", nil)
+			Expect(err).NotTo(HaveOccurred())
+			wait, err := registry.FloatGauge("pool_wait_seconds", "Wait time", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			calls := 0
+			registration, err := registry.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+				calls++
+				o.ObserveInt64(idle, 5)
+				o.ObserveFloat64(wait, 0.25)
+				return nil
+			}, idle, wait)
+			Expect(err).NotTo(HaveOccurred())
+
+			metrics := collectMetrics(ctx, reader)
+			Expect(calls).To(Equal(1))
+			Expect(metrics["app_pool_idle"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(Equal(int64(5)))
+			Expect(metrics["app_pool_wait_seconds"].Data.(metricdata.Gauge[float64]).DataPoints[0].Value).To(Equal(0.25))
+
+			Expect(registration.Unregister()).To(Succeed())
+			Expect(registration.Unregister()).To(Succeed())
+
+			collectMetrics(ctx, reader)
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should remove unregistered instruments from the registry", func() {
+			idle, err := registry.ObservableUpDownCounter("pool_idle", "Idle connections", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			registration, err := registry.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+				o.ObserveInt64(idle, 5)
+				return nil
+			}, idle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gotel.IsRegistered(registry, "app_pool_idle")).To(BeTrue())
+
+			Expect(registration.Unregister()).To(Succeed())
+			Expect(gotel.IsRegistered(registry, "app_pool_idle")).To(BeFalse())
+
+			_, err = registry.ObservableUpDownCounter("pool_idle", "Idle connections", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gotel.IsRegistered(registry, "app_pool_idle")).To(BeTrue())
+		})
+
+		It("should keep rejecting another kind for the name of an unregistered instrument", func() {
+			idle, err := registry.ObservableUpDownCounter("pool_idle", "Idle connections", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			registration, err := registry.RegisterCallback(func(context.Context, metric.Observer) error {
+				return nil
+			}, idle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(registration.Unregister()).To(Succeed())
+
+			_, err = registry.FloatGauge("pool_idle", "Idle connections", nil)
+			Expect(err).To(MatchError(gotel.ErrInstrumentKindConflict))
+		})
+
+		It("should apply cardinality limits to observations", func() {
+			reader = sdkmetric.NewManualReader()
+			meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("limited")
+			limited := gotel.NewMetricRegistry(meter, "", gotel.WithCardinalityLimit(1))
+
+			sessions, err := limited.ObservableUpDownCounter("sessions", "Sessions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = limited.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+				o.ObserveInt64(sessions, 1, metric.WithAttributes(attribute.String("tenant", "a")))
+				o.ObserveInt64(sessions, 2, metric.WithAttributes(attribute.String("tenant", "b")))
+				o.ObserveInt64(sessions, 3, metric.WithAttributes(attribute.String("tenant", "c")))
+				return nil
+			}, sessions)
+			Expect(err).NotTo(HaveOccurred())
+
+			points := collectMetrics(ctx, reader)["sessions"].Data.(metricdata.Sum[int64]).DataPoints
+			Expect(points).To(HaveLen(2))
+		})
+	})
+})
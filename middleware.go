@@ -4,12 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
@@ -20,15 +20,45 @@ type HTTPMiddleware struct {
 	serviceName string
 	tracer      *Tracer
 	metrics     *CommonMetrics
+	routes      *boundCache[httpMetricsKey, *httpRouteMetrics]
+	statuses    *boundCache[httpMetricsKey, *httpStatusMetrics]
+}
+
+// httpMetricsKey identifies a set of bound HTTP metric attributes. route is
+// the raw request path used by the legacy metrics and pattern the matched
+// route template. pattern and status are empty for metrics that are not
+// tagged with them.
+type httpMetricsKey struct {
+	method  string
+	route   string
+	pattern string
+	scheme  string
+	status  int
+}
+
+// httpRouteMetrics holds the instruments bound to a method and route.
+type httpRouteMetrics struct {
+	active        *BoundUpDownCounter
+	duration      *BoundHistogram
+	semconvActive *BoundUpDownCounter
+}
+
+// httpStatusMetrics holds the instruments bound to a method, route and status.
+type httpStatusMetrics struct {
+	total           *BoundCounter
+	semconvDuration *BoundHistogram
 }
 
 // NewHTTPMiddleware creates and returns a new HTTPMiddleware instance.
 func NewHTTPMiddleware(serviceName string, tracer *Tracer, metrics *CommonMetrics) *HTTPMiddleware {
-	return &HTTPMiddleware{
+	m := &HTTPMiddleware{
 		tracer:      tracer,
 		metrics:     metrics,
 		serviceName: serviceName,
 	}
+	m.routes = newBoundCache[httpMetricsKey](m.overflowRouteMetrics)
+	m.statuses = newBoundCache[httpMetricsKey](m.overflowStatusMetrics)
+	return m
 }
 
 func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
@@ -45,18 +75,19 @@ func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
 		)
 		defer span.End()
 
-		attrs := []attribute.KeyValue{
-			attribute.String("method", r.Method),
-			attribute.String("route", r.URL.Path),
-		}
-
-		m.metrics.HTTPActiveRequests.Add(ctx, 1, metric.WithAttributes(attrs...))
-		defer m.metrics.HTTPActiveRequests.Add(ctx, -1, metric.WithAttributes(attrs...))
+		key := httpMetricsKey{method: r.Method, route: r.URL.Path, scheme: requestScheme(r)}
+		route := m.routeMetrics(key)
+		route.active.Add(ctx, 1)
+		route.semconvActive.Add(ctx, 1)
+		defer route.active.Add(ctx, -1)
+		defer route.semconvActive.Add(ctx, -1)
 
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
+		// A ServeMux sets the matched pattern on the request it is handed.
+		req := r.WithContext(ctx)
 		start := time.Now()
-		next.ServeHTTP(wrapped, r.WithContext(ctx))
+		next.ServeHTTP(wrapped, req)
 		duration := time.Since(start)
 
 		span.WithAttributes(semconv.HTTPResponseStatusCodeKey.Int(wrapped.statusCode))
@@ -64,12 +95,116 @@ func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
 			span.WithStatus(codes.Error, "HTTP Request Failed")
 		}
 
-		statusAttrs := append(attrs, attribute.String("status_code", strconv.Itoa(wrapped.statusCode)))
-		m.metrics.HTTPRequestsTotal.Add(ctx, 1, metric.WithAttributes(statusAttrs...))
-		m.metrics.HTTPRequestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+		key.pattern = routeFromPattern(req.Pattern)
+		key.status = wrapped.statusCode
+		status := m.statusMetrics(key)
+		status.total.Add(ctx, 1)
+		route.duration.Record(ctx, duration.Seconds())
+		status.semconvDuration.Record(ctx, duration.Seconds())
+	})
+}
+
+// routeMetrics returns the instruments bound to the key's method and route.
+func (m *HTTPMiddleware) routeMetrics(key httpMetricsKey) *httpRouteMetrics {
+	return m.routes.get(key, func() *httpRouteMetrics {
+		attrs := []attribute.KeyValue{
+			attribute.String("method", key.method),
+			attribute.String("route", key.route),
+		}
+		semconvAttrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(semconvMethod(key.method)),
+			semconv.URLSchemeKey.String(key.scheme),
+		}
+		return &httpRouteMetrics{
+			active:        BindUpDownCounter(m.metrics.HTTPActiveRequests, attrs...),
+			duration:      BindHistogram(m.metrics.HTTPRequestDuration, attrs...),
+			semconvActive: BindUpDownCounter(m.metrics.HTTPServerActiveRequests, semconvAttrs...),
+		}
+	})
+}
+
+// statusMetrics returns the instruments bound to the key's method, route and status.
+func (m *HTTPMiddleware) statusMetrics(key httpMetricsKey) *httpStatusMetrics {
+	return m.statuses.get(key, func() *httpStatusMetrics {
+		return &httpStatusMetrics{
+			total: BindCounter(m.metrics.HTTPRequestsTotal,
+				attribute.String("method", key.method),
+				attribute.String("route", key.route),
+				attribute.String("status_code", strconv.Itoa(key.status)),
+			),
+			semconvDuration: BindHistogram(m.metrics.HTTPServerRequestDuration, semconvStatusAttrs(key)...),
+		}
 	})
 }
 
+// semconvStatusAttrs returns the http.server.request.duration attributes.
+// http.route is only set when a route template matched, and error.type only
+// for server errors.
+func semconvStatusAttrs(key httpMetricsKey) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(semconvMethod(key.method)),
+		semconv.HTTPResponseStatusCodeKey.Int(key.status),
+		semconv.URLSchemeKey.String(key.scheme),
+	}
+	if key.pattern != "" {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(key.pattern))
+	}
+	if key.status >= http.StatusInternalServerError {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(strconv.Itoa(key.status)))
+	}
+	return attrs
+}
+
+// routeFromPattern returns the path template of a ServeMux pattern such as
+// "GET example.com/users/{id}", or "" when no pattern matched.
+func routeFromPattern(pattern string) string {
+	if i := strings.IndexByte(pattern, '/'); i >= 0 {
+		return pattern[i:]
+	}
+	return ""
+}
+
+// overflowRouteMetrics returns route instruments bound to the overflow
+// attribute set, used once the route cache is full.
+func (m *HTTPMiddleware) overflowRouteMetrics() *httpRouteMetrics {
+	attrs := overflowAttrs.ToSlice()
+	return &httpRouteMetrics{
+		active:        BindUpDownCounter(m.metrics.HTTPActiveRequests, attrs...),
+		duration:      BindHistogram(m.metrics.HTTPRequestDuration, attrs...),
+		semconvActive: BindUpDownCounter(m.metrics.HTTPServerActiveRequests, attrs...),
+	}
+}
+
+// overflowStatusMetrics returns status instruments bound to the overflow
+// attribute set, used once the status cache is full.
+func (m *HTTPMiddleware) overflowStatusMetrics() *httpStatusMetrics {
+	attrs := overflowAttrs.ToSlice()
+	return &httpStatusMetrics{
+		total:           BindCounter(m.metrics.HTTPRequestsTotal, attrs...),
+		semconvDuration: BindHistogram(m.metrics.HTTPServerRequestDuration, attrs...),
+	}
+}
+
+// semconvMethod returns method if it is a known HTTP method and _OTHER
+// otherwise, as the semantic conventions require to bound cardinality.
+func semconvMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "_OTHER"
+	}
+}
+
+// requestScheme returns the URL scheme the request was served over.
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
 type responseWriter struct {
 	statusCode int
 	http.ResponseWriter
@@ -0,0 +1,119 @@
+package gotel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidMetricName is returned when a generated metric name does not match
+// the OpenTelemetry instrument name grammar.
+var ErrInvalidMetricName = errors.New("invalid metric name")
+
+// instrumentNamePattern is the instrument name grammar from the OpenTelemetry
+// specification: an ASCII letter followed by up to 254 letters, digits, '_',
+// '.', '-' or '/'.
+var instrumentNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_./-]{0,254}$`)
+
+// Separators joining the namespace, prefix and name of a metric.
+const (
+	SeparatorUnderscore = "_"
+	SeparatorDot        = "."
+)
+
+// UnitSuffixRule controls how a metric name relates to its instrument's unit.
+type UnitSuffixRule int
+
+const (
+	// UnitSuffixKeep uses names exactly as given.
+	UnitSuffixKeep UnitSuffixRule = iota
+	// UnitSuffixAppend appends the unit's suffix, such as seconds for "s", to
+	// names that do not already end with it, as Prometheus naming expects. The
+	// suffix goes before a trailing _total, as in process_cpu_seconds_total.
+	UnitSuffixAppend
+	// UnitSuffixStrip removes the unit's suffix from names, including one
+	// followed by _total, since OpenTelemetry carries the unit as instrument
+	// metadata instead.
+	UnitSuffixStrip
+)
+
+// unitSuffixes maps UCUM units to the suffix used for them in metric names.
+// Units not listed here, including annotations like {request}, have no suffix.
+var unitSuffixes = map[string]string{
+	"ns":   "nanoseconds",
+	"us":   "microseconds",
+	"ms":   "milliseconds",
+	"s":    "seconds",
+	"min":  "minutes",
+	"h":    "hours",
+	"By":   "bytes",
+	"KiBy": "kibibytes",
+	"MiBy": "mebibytes",
+	"1":    "ratio",
+	"%":    "percent",
+}
+
+// NamingPolicy describes how a MetricRegistry turns the names it is given into
+// instrument names.
+type NamingPolicy struct {
+	// Namespace is prepended before the registry prefix, e.g. an organisation
+	// or product name shared by several services.
+	Namespace string
+	// Separator joins the namespace, prefix and name. Defaults to "_".
+	Separator string
+	// UnitSuffix controls whether unit suffixes are appended or stripped.
+	UnitSuffix UnitSuffixRule
+}
+
+// WithNamingPolicy sets the naming policy of the registry. Every generated
+// name is validated, and instruments with illegal names fail to be created
+// with ErrInvalidMetricName.
+func WithNamingPolicy(policy NamingPolicy) RegistryOption {
+	return func(m *MetricRegistry) {
+		if policy.Separator == "" {
+			policy.Separator = SeparatorUnderscore
+		}
+		m.naming = policy
+	}
+}
+
+// generateMetricName applies the naming policy to name and validates the result.
+func (m *MetricRegistry) generateMetricName(name, unit string) (string, error) {
+	separator := m.naming.Separator
+	if separator == "" {
+		separator = SeparatorUnderscore
+	}
+
+	if suffix, ok := unitSuffixes[unit]; ok {
+		// The unit suffix belongs before the counter suffix, so split it off
+		// and put it back once the rule has been applied.
+		base, total := name, ""
+		if trimmed, ok := strings.CutSuffix(name, separator+"total"); ok && trimmed != "" {
+			base, total = trimmed, separator+"total"
+		}
+
+		switch m.naming.UnitSuffix {
+		case UnitSuffixAppend:
+			if !strings.HasSuffix(base, separator+suffix) {
+				base += separator + suffix
+			}
+		case UnitSuffixStrip:
+			base = strings.TrimSuffix(base, separator+suffix)
+		}
+		name = base + total
+	}
+
+	parts := make([]string, 0, 3)
+	for _, part := range []string{m.naming.Namespace, m.prefix, name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	metricName := strings.Join(parts, separator)
+	if !instrumentNamePattern.MatchString(metricName) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidMetricName, metricName)
+	}
+	return metricName, nil
+}
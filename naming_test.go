@@ -0,0 +1,81 @@
+package gotel_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("NamingPolicy", func() {
+	var (
+		ctx    context.Context
+		reader *sdkmetric.ManualReader
+		meter  metric.Meter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+		meter = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("naming-test")
+	})
+
+	It("should prefix every common metric exactly once", func() {
+		_, err := gotel.NewCommonMetrics(gotel.NewMetricRegistry(meter, "app"))
+		Expect(err).NotTo(HaveOccurred())
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics).To(HaveKey("app_start_time_seconds"))
+		Expect(metrics).NotTo(HaveKey("app_app_start_time_seconds"))
+	})
+
+	It("should join the namespace, prefix and name with the separator", func() {
+		registry := gotel.NewMetricRegistry(meter, "billing", gotel.WithNamingPolicy(gotel.NamingPolicy{
+			Namespace: "acme",
+			Separator: gotel.SeparatorDot,
+		}))
+
+		counter, err := registry.Counter("invoices", "Invoices")
+		Expect(err).NotTo(HaveOccurred())
+		counter.Add(ctx, 1)
+
+		Expect(collectMetrics(ctx, reader)).To(HaveKey("acme.billing.invoices"))
+	})
+
+	DescribeTable("unit suffix rules",
+		func(rule gotel.UnitSuffixRule, name, expected string) {
+			registry := gotel.NewMetricRegistry(meter, "app", gotel.WithNamingPolicy(gotel.NamingPolicy{UnitSuffix: rule}))
+
+			histogram, err := registry.Histogram(name, "Latency", metric.WithUnit("s"))
+			Expect(err).NotTo(HaveOccurred())
+			histogram.Record(ctx, 0.1)
+
+			Expect(collectMetrics(ctx, reader)).To(HaveKey(expected))
+		},
+		Entry("keep", gotel.UnitSuffixKeep, "latency", "app_latency"),
+		Entry("append", gotel.UnitSuffixAppend, "latency", "app_latency_seconds"),
+		Entry("append when already suffixed", gotel.UnitSuffixAppend, "latency_seconds", "app_latency_seconds"),
+		Entry("strip", gotel.UnitSuffixStrip, "latency_seconds", "app_latency"),
+		Entry("append before total", gotel.UnitSuffixAppend, "cpu_total", "app_cpu_seconds_total"),
+		Entry("append when already suffixed before total", gotel.UnitSuffixAppend, "cpu_seconds_total", "app_cpu_seconds_total"),
+		Entry("strip before total", gotel.UnitSuffixStrip, "cpu_seconds_total", "app_cpu_total"),
+	)
+
+	DescribeTable("rejecting names outside the instrument name grammar",
+		func(prefix, name string) {
+			_, err := gotel.NewMetricRegistry(meter, prefix).Counter(name, "Invalid")
+			Expect(errors.Is(err, gotel.ErrInvalidMetricName)).To(BeTrue())
+		},
+		Entry("leading digit", "", "5xx_total"),
+		Entry("space", "app", "requests total"),
+		Entry("illegal character", "app", "requests:total"),
+		Entry("too long", "", "a"+strings.Repeat("b", 255)),
+		Entry("illegal prefix", "my app", "requests_total"),
+	)
+})
@@ -0,0 +1,88 @@
+package gotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// runStartupProbe verifies the collector is reachable when the startup probe
+// is enabled, and either fails or warns depending on the configured mode.
+func (p *Provider) runStartupProbe(ctx context.Context) error {
+	probe := p.config.StartupProbe
+	if !probe.Enabled || p.config.Debug {
+		return nil
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, probe.Timeout)
+	defer cancel()
+
+	err := p.probeCollector(probeCtx)
+	if err == nil {
+		return nil
+	}
+
+	if probe.FailMode == ProbeFailWarn {
+		p.logger.Warn(ctx, "OTLP collector startup probe failed",
+			zap.String("endpoint", p.config.Exporter.Endpoint),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	return fmt.Errorf("startup probe to %s failed: %w", p.config.Exporter.Endpoint, err)
+}
+
+// probeCollector sends an empty export request for every signal to the
+// configured endpoint. The SDK exporters skip empty batches, so the probe talks
+// to the collector services directly over its own connection.
+func (p *Provider) probeCollector(ctx context.Context) error {
+	creds := insecure.NewCredentials()
+	if !p.config.Security.Insecure {
+		creds = p.config.Security.TLSCredentials
+		if creds == nil {
+			creds = credentials.NewTLS(nil)
+		}
+	}
+
+	conn, err := grpc.NewClient(p.config.Exporter.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return fmt.Errorf("failed to create probe connection: %w", err)
+	}
+	defer conn.Close()
+
+	if len(p.config.Exporter.Headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(p.config.Exporter.Headers))
+	}
+
+	var errs []error
+
+	if _, err := collectortrace.NewTraceServiceClient(conn).Export(
+		ctx, &collectortrace.ExportTraceServiceRequest{},
+	); err != nil {
+		errs = append(errs, fmt.Errorf("trace export rejected: %w", err))
+	}
+
+	if _, err := collectormetrics.NewMetricsServiceClient(conn).Export(
+		ctx, &collectormetrics.ExportMetricsServiceRequest{},
+	); err != nil {
+		errs = append(errs, fmt.Errorf("metric export rejected: %w", err))
+	}
+
+	if _, err := collectorlogs.NewLogsServiceClient(conn).Export(
+		ctx, &collectorlogs.ExportLogsServiceRequest{},
+	); err != nil {
+		errs = append(errs, fmt.Errorf("log export rejected: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
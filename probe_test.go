@@ -0,0 +1,190 @@
+package gotel_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+
+	"github.com/iamBelugax/gotel"
+)
+
+type fakeTraceService struct {
+	collectortrace.UnimplementedTraceServiceServer
+}
+
+func (fakeTraceService) Export(
+	context.Context, *collectortrace.ExportTraceServiceRequest,
+) (*collectortrace.ExportTraceServiceResponse, error) {
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+type fakeMetricsService struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+
+	mu      sync.Mutex
+	metrics map[string]*metricspb.Metric
+}
+
+func (s *fakeMetricsService) Export(
+	_ context.Context, req *collectormetrics.ExportMetricsServiceRequest,
+) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metrics == nil {
+		s.metrics = make(map[string]*metricspb.Metric)
+	}
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				s.metrics[m.Name] = m
+			}
+		}
+	}
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+// received returns the last exported data for the named metric.
+func (s *fakeMetricsService) received(name string) *metricspb.Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.metrics[name]
+}
+
+type fakeLogsService struct {
+	collectorlogs.UnimplementedLogsServiceServer
+}
+
+func (fakeLogsService) Export(
+	context.Context, *collectorlogs.ExportLogsServiceRequest,
+) (*collectorlogs.ExportLogsServiceResponse, error) {
+	return &collectorlogs.ExportLogsServiceResponse{}, nil
+}
+
+// startFakeCollector serves the given OTLP services on a local port and
+// returns its address.
+func startFakeCollector(register func(*grpc.Server)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(lis)
+	DeferCleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+// captureStdout returns everything written to os.Stdout while fn runs, which
+// includes the console output of loggers created by fn.
+func captureStdout(fn func()) string {
+	r, w, err := os.Pipe()
+	Expect(err).NotTo(HaveOccurred())
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	fn()
+	Expect(w.Close()).To(Succeed())
+	return <-output
+}
+
+var _ = Describe("Startup probe", func() {
+	var (
+		ctx          context.Context
+		probeTimeout = 500 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	newProvider := func(endpoint string, mode gotel.ProbeFailMode) (*gotel.Provider, error) {
+		return gotel.NewProvider(ctx,
+			gotel.WithInsecure(true),
+			gotel.WithEndpoint(endpoint),
+			gotel.WithExportTimeout(probeTimeout),
+			gotel.WithStartupProbe(probeTimeout, mode),
+			gotel.WithServiceInfo("probe-test", "1.0.0", "test"),
+		)
+	}
+
+	It("should succeed when the collector accepts all signals", func() {
+		endpoint := startFakeCollector(func(s *grpc.Server) {
+			collectortrace.RegisterTraceServiceServer(s, fakeTraceService{})
+			collectormetrics.RegisterMetricsServiceServer(s, &fakeMetricsService{})
+			collectorlogs.RegisterLogsServiceServer(s, fakeLogsService{})
+		})
+
+		provider, err := newProvider(endpoint, gotel.ProbeFailError)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider.Shutdown(ctx)).To(Succeed())
+	})
+
+	It("should fail when a signal is not accepted by the collector", func() {
+		endpoint := startFakeCollector(func(s *grpc.Server) {
+			collectortrace.RegisterTraceServiceServer(s, fakeTraceService{})
+			collectorlogs.RegisterLogsServiceServer(s, fakeLogsService{})
+		})
+
+		provider, err := newProvider(endpoint, gotel.ProbeFailError)
+		Expect(provider).To(BeNil())
+		Expect(err).To(MatchError(ContainSubstring("metric export rejected")))
+	})
+
+	It("should fail when the collector is unreachable", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		endpoint := lis.Addr().String()
+		Expect(lis.Close()).To(Succeed())
+
+		tracerProvider, meterProvider := otel.GetTracerProvider(), otel.GetMeterProvider()
+
+		provider, err := newProvider(endpoint, gotel.ProbeFailError)
+		Expect(provider).To(BeNil())
+		Expect(err).To(MatchError(ContainSubstring("startup probe")))
+
+		Expect(otel.GetTracerProvider()).To(BeIdenticalTo(tracerProvider))
+		Expect(otel.GetMeterProvider()).To(BeIdenticalTo(meterProvider))
+	})
+
+	It("should only warn when configured to do so", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		endpoint := lis.Addr().String()
+		Expect(lis.Close()).To(Succeed())
+
+		var provider *gotel.Provider
+		output := captureStdout(func() {
+			provider, err = newProvider(endpoint, gotel.ProbeFailWarn)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider).NotTo(BeNil())
+		Expect(output).To(ContainSubstring(`"level":"warn"`))
+		Expect(output).To(ContainSubstring("OTLP collector startup probe failed"))
+		Expect(output).To(ContainSubstring(endpoint))
+
+		shutdownCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		defer cancel()
+		_ = provider.Shutdown(shutdownCtx)
+	})
+})
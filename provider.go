@@ -2,17 +2,24 @@ package gotel
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"net/http"
+	"sync"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
@@ -21,8 +28,6 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // Provider is the central struct that encapsulates all OpenTelemetry SDK components.
@@ -37,16 +42,30 @@ type Provider struct {
 	metricExporter sdkmetric.Exporter
 	logExporter    sdklog.Exporter
 
-	tracer trace.Tracer
-	meter  metric.Meter
-	logger *ZapLogger
+	tracer         trace.Tracer
+	meter          metric.Meter
+	logger         *ZapLogger
+	metricsHandler http.Handler
+
+	hooksMu       sync.Mutex
+	flushHooks    []LifecycleHook
+	shutdownHooks []LifecycleHook
 }
 
+// LifecycleHook is a function invoked by the Provider when it flushes or
+// shuts down, allowing other components to take part in the same lifecycle.
+type LifecycleHook func(ctx context.Context) error
+
 // NewProvider initializes and configures the OpenTelemetry SDK based on the provided configuration.
 // It sets up the resource, exporters and providers for tracing, metrics and logging.
 func NewProvider(ctx context.Context, opts ...Option) (*Provider, error) {
 	conf := DefaultConfig(opts...)
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+
 	p := &Provider{config: conf}
+	globals := currentGlobalProviders()
 
 	resource, err := p.createResource(ctx)
 	if err != nil {
@@ -54,20 +73,76 @@ func NewProvider(ctx context.Context, opts ...Option) (*Provider, error) {
 	}
 
 	if err := p.initTracing(ctx, resource); err != nil {
-		return nil, err
+		return nil, p.abort(ctx, globals, err)
 	}
 
 	if err := p.initMetrics(ctx, resource); err != nil {
-		return nil, err
+		return nil, p.abort(ctx, globals, err)
 	}
 
 	if err := p.initLogging(ctx, resource); err != nil {
-		return nil, err
+		return nil, p.abort(ctx, globals, err)
+	}
+
+	if err := p.initSpanMetrics(); err != nil {
+		return nil, p.abort(ctx, globals, err)
+	}
+
+	if err := p.initServiceGraph(); err != nil {
+		return nil, p.abort(ctx, globals, err)
+	}
+
+	if err := p.runStartupProbe(ctx); err != nil {
+		return nil, p.abort(ctx, globals, err)
 	}
 
 	return p, nil
 }
 
+// abort undoes a NewProvider that failed with err: it reinstalls the global
+// providers captured before startup and shuts down every provider created so
+// far, joining their errors to err. The shutdown ignores the cancellation of
+// ctx, which may be what made startup fail.
+func (p *Provider) abort(ctx context.Context, globals globalProviders, err error) error {
+	globals.restore()
+	ctx = context.WithoutCancel(ctx)
+
+	errs := []error{err}
+	if p.traceProvider != nil {
+		errs = append(errs, p.traceProvider.Shutdown(ctx))
+	}
+	if p.metricProvider != nil {
+		errs = append(errs, p.metricProvider.Shutdown(ctx))
+	}
+	if p.logProvider != nil {
+		errs = append(errs, p.logProvider.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// globalProviders holds the global providers in place before NewProvider
+// installed its own, so they can be restored when startup fails.
+type globalProviders struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	loggerProvider log.LoggerProvider
+}
+
+func currentGlobalProviders() globalProviders {
+	return globalProviders{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		loggerProvider: global.GetLoggerProvider(),
+	}
+}
+
+// restore reinstalls the captured providers as the globals.
+func (g globalProviders) restore() {
+	otel.SetTracerProvider(g.tracerProvider)
+	otel.SetMeterProvider(g.meterProvider)
+	global.SetLoggerProvider(g.loggerProvider)
+}
+
 // Tracer returns the configured tracer.
 func (p *Provider) Tracer() trace.Tracer {
 	return p.tracer
@@ -78,15 +153,89 @@ func (p *Provider) Meter() metric.Meter {
 	return p.meter
 }
 
+// TemporalitySelector returns the temporality the provider exports each
+// instrument kind with, as configured by WithTemporality and
+// WithTemporalityOverride.
+func (p *Provider) TemporalitySelector() sdkmetric.TemporalitySelector {
+	return p.config.Metrics.temporalitySelector()
+}
+
+// MetricsHandler returns an http.Handler serving the provider's metrics in the
+// Prometheus exposition format, including resource attributes as target_info.
+// It responds with 404 unless the provider was created WithPrometheus(true).
+func (p *Provider) MetricsHandler() http.Handler {
+	if p.metricsHandler == nil {
+		return http.NotFoundHandler()
+	}
+	return p.metricsHandler
+}
+
 // Logger returns the configured Zap logger.
 func (p *Provider) Logger() *ZapLogger {
 	return p.logger
 }
 
-// Shutdown gracefully shuts down all telemetry exporters.
+// OnFlush registers a hook that runs every time ForceFlush is called.
+// Hooks run in registration order, before the SDK providers are flushed.
+func (p *Provider) OnFlush(hook LifecycleHook) {
+	p.hooksMu.Lock()
+	defer p.hooksMu.Unlock()
+	p.flushHooks = append(p.flushHooks, hook)
+}
+
+// OnShutdown registers a hook that runs when Shutdown is called.
+// Hooks run in reverse registration order, before the SDK providers are shut down.
+func (p *Provider) OnShutdown(hook LifecycleHook) {
+	p.hooksMu.Lock()
+	defer p.hooksMu.Unlock()
+	p.shutdownHooks = append(p.shutdownHooks, hook)
+}
+
+// ForceFlush exports all pending telemetry without shutting down the provider.
+// Errors from hooks and from each signal are joined, so callers can use errors.Is.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	var errs []error
+
+	p.hooksMu.Lock()
+	hooks := append([]LifecycleHook(nil), p.flushHooks...)
+	p.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("flush hook failed: %w", err))
+		}
+	}
+
+	if err := p.traceProvider.ForceFlush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush trace provider: %w", err))
+	}
+
+	if err := p.metricProvider.ForceFlush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush metric provider: %w", err))
+	}
+
+	if err := p.logProvider.ForceFlush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush log provider: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shutdown gracefully shuts down all telemetry exporters. Errors from hooks and
+// from each signal are joined, so callers can use errors.Is.
 func (p *Provider) Shutdown(ctx context.Context) error {
 	var errs []error
 
+	p.hooksMu.Lock()
+	hooks := append([]LifecycleHook(nil), p.shutdownHooks...)
+	p.hooksMu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook failed: %w", err))
+		}
+	}
+
 	if err := p.traceProvider.Shutdown(ctx); err != nil {
 		errs = append(errs, fmt.Errorf("failed to shutdown trace provider: %w", err))
 	}
@@ -99,10 +248,7 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 		errs = append(errs, fmt.Errorf("failed to shutdown log provider: %w", err))
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("shutdown failed with errors: %v", errs)
-	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // createResource builds an OTEL resource from service metadata and custom attributes.
@@ -142,13 +288,9 @@ func (p *Provider) initTracing(ctx context.Context, resource *resource.Resource)
 		}
 
 		if p.config.Security.Insecure {
-			opts = append(opts, otlptracegrpc.WithDialOption(
-				grpc.WithTransportCredentials(insecure.NewCredentials()),
-			))
+			opts = append(opts, otlptracegrpc.WithInsecure())
 		} else {
-			opts = append(opts, otlptracegrpc.WithDialOption(
-				grpc.WithTransportCredentials(p.config.Security.TLSCredentials),
-			))
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(p.config.Security.TLSCredentials))
 		}
 
 		if len(p.config.Exporter.Headers) > 0 {
@@ -180,6 +322,37 @@ func (p *Provider) initTracing(ctx context.Context, resource *resource.Resource)
 	return nil
 }
 
+// initSpanMetrics registers the span metrics processor, which needs both the
+// tracer and the meter provider to be set up.
+func (p *Provider) initSpanMetrics() error {
+	if !p.config.Tracing.SpanMetrics.Enabled {
+		return nil
+	}
+
+	processor, err := NewSpanMetricsProcessor(p.meter, p.config.Tracing.SpanMetrics)
+	if err != nil {
+		return fmt.Errorf("failed to create span metrics processor: %w", err)
+	}
+
+	p.traceProvider.RegisterSpanProcessor(processor)
+	return nil
+}
+
+// initServiceGraph registers the service graph processor.
+func (p *Provider) initServiceGraph() error {
+	if !p.config.Tracing.ServiceGraph.Enabled {
+		return nil
+	}
+
+	processor, err := NewServiceGraphProcessor(p.meter, p.config.Tracing.ServiceGraph)
+	if err != nil {
+		return fmt.Errorf("failed to create service graph processor: %w", err)
+	}
+
+	p.traceProvider.RegisterSpanProcessor(processor)
+	return nil
+}
+
 // initMetrics sets up the meter provider and exporter.
 func (p *Provider) initMetrics(ctx context.Context, res *resource.Resource) error {
 	var (
@@ -187,22 +360,31 @@ func (p *Provider) initMetrics(ctx context.Context, res *resource.Resource) erro
 		exporter sdkmetric.Exporter
 	)
 
+	reservoirs := p.config.Metrics.Exemplars.reservoirSelector()
+	views := make([]sdkmetric.View, 0, len(p.config.Metrics.Views)+1)
+	for _, view := range p.config.Metrics.Views {
+		views = append(views, view.sdkView(reservoirs))
+	}
+	if reservoirs != nil {
+		views = append(views, exemplarView(views, reservoirs))
+	}
+
 	if p.config.Debug {
-		exporter, err = stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+		exporter, err = stdoutmetric.New(
+			stdoutmetric.WithPrettyPrint(),
+			stdoutmetric.WithTemporalitySelector(p.config.Metrics.temporalitySelector()),
+		)
 	} else {
 		opts := []otlpmetricgrpc.Option{
 			otlpmetricgrpc.WithEndpoint(p.config.Exporter.Endpoint),
 			otlpmetricgrpc.WithTimeout(p.config.Exporter.ExportTimeout),
+			otlpmetricgrpc.WithTemporalitySelector(p.config.Metrics.temporalitySelector()),
 		}
 
 		if p.config.Security.Insecure {
-			opts = append(opts, otlpmetricgrpc.WithDialOption(
-				grpc.WithTransportCredentials(insecure.NewCredentials()),
-			))
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
 		} else {
-			opts = append(opts, otlpmetricgrpc.WithDialOption(
-				grpc.WithTransportCredentials(p.config.Security.TLSCredentials),
-			))
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(p.config.Security.TLSCredentials))
 		}
 
 		if len(p.config.Exporter.Headers) > 0 {
@@ -216,15 +398,57 @@ func (p *Provider) initMetrics(ctx context.Context, res *resource.Resource) erro
 		return fmt.Errorf("failed to create metric exporter: %w", err)
 	}
 
+	// Each reader gets its own runtime producer, since delta temporality keeps
+	// per-reader state. Prometheus always reports cumulative data.
+	var periodicOpts []sdkmetric.PeriodicReaderOption
+	var promOpts []prometheus.Option
+	if p.config.Metrics.Runtime.Enabled {
+		selector := p.config.Metrics.temporalitySelector()
+		periodicOpts = append(periodicOpts, sdkmetric.WithProducer(NewRuntimeProducer(selector)))
+		promOpts = append(promOpts, prometheus.WithProducer(NewRuntimeProducer(sdkmetric.DefaultTemporalitySelector)))
+	}
+
+	var promReader *prometheus.Exporter
+	if p.config.Metrics.Prometheus {
+		registry := prom.NewRegistry()
+		promReader, err = prometheus.New(append(promOpts, prometheus.WithRegisterer(registry))...)
+		if err != nil {
+			return errors.Join(
+				fmt.Errorf("failed to create prometheus exporter: %w", err),
+				exporter.Shutdown(ctx),
+			)
+		}
+		p.metricsHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	}
+
 	p.metricExporter = exporter
-	p.metricProvider = sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(
-			sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(15*time.Second)),
-		),
+	providerOpts := []sdkmetric.Option{
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, append(periodicOpts,
+			sdkmetric.WithInterval(p.config.Metrics.Interval),
+			sdkmetric.WithTimeout(p.config.Metrics.ExportTimeout),
+		)...)),
 		sdkmetric.WithResource(res),
-	)
-	otel.SetMeterProvider(p.metricProvider)
+		sdkmetric.WithView(views...),
+		sdkmetric.WithExemplarFilter(p.config.Metrics.Exemplars.sdkFilter()),
+	}
+	for _, reader := range p.config.Metrics.Readers {
+		providerOpts = append(providerOpts, sdkmetric.WithReader(reader))
+	}
+	if promReader != nil {
+		providerOpts = append(providerOpts, sdkmetric.WithReader(promReader))
+	}
+
+	p.metricProvider = sdkmetric.NewMeterProvider(providerOpts...)
 	p.meter = p.metricProvider.Meter(p.config.Service.Name)
+
+	if p.config.Metrics.Runtime.Enabled {
+		runtimeMetrics := newRuntimeReader(p.config.Metrics.Runtime.MinReadInterval)
+		if err := runtimeMetrics.initRuntimeMetrics(p.meter); err != nil {
+			return fmt.Errorf("failed to create runtime metrics: %w", err)
+		}
+	}
+
+	otel.SetMeterProvider(p.metricProvider)
 	return nil
 }
 
@@ -244,13 +468,9 @@ func (p *Provider) initLogging(ctx context.Context, res *resource.Resource) erro
 		}
 
 		if p.config.Security.Insecure {
-			opts = append(opts, otlploggrpc.WithDialOption(
-				grpc.WithTransportCredentials(insecure.NewCredentials()),
-			))
+			opts = append(opts, otlploggrpc.WithInsecure())
 		} else {
-			opts = append(opts, otlploggrpc.WithDialOption(
-				grpc.WithTransportCredentials(p.config.Security.TLSCredentials),
-			))
+			opts = append(opts, otlploggrpc.WithTLSCredentials(p.config.Security.TLSCredentials))
 		}
 
 		if len(p.config.Exporter.Headers) > 0 {
@@ -0,0 +1,180 @@
+package gotel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Provider", func() {
+	var (
+		ctx      context.Context
+		provider *gotel.Provider
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		provider, err = gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithServiceInfo("provider-test", "1.0.0", "test"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("NewProvider", func() {
+		It("should shut down what it created and restore the globals when startup fails", func() {
+			tracerProvider := otel.GetTracerProvider()
+			meterProvider := otel.GetMeterProvider()
+			loggerProvider := global.GetLoggerProvider()
+
+			reader := sdkmetric.NewManualReader()
+			_, err := gotel.NewProvider(ctx,
+				gotel.WithDebug(true),
+				gotel.WithMetricReaders(reader),
+				gotel.WithSpanMetrics(),
+				gotel.WithSpanMetricsBuckets(1, 0.5),
+			)
+			Expect(err).To(MatchError(ContainSubstring("failed to create span metrics processor")))
+
+			Expect(otel.GetTracerProvider()).To(BeIdenticalTo(tracerProvider))
+			Expect(otel.GetMeterProvider()).To(BeIdenticalTo(meterProvider))
+			Expect(global.GetLoggerProvider()).To(BeIdenticalTo(loggerProvider))
+
+			var rm metricdata.ResourceMetrics
+			Expect(reader.Collect(ctx, &rm)).To(MatchError(sdkmetric.ErrReaderShutdown))
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+		})
+	})
+
+	Context("ForceFlush", func() {
+		It("should run flush hooks in registration order", func() {
+			var calls []string
+			provider.OnFlush(func(context.Context) error {
+				calls = append(calls, "first")
+				return nil
+			})
+			provider.OnFlush(func(context.Context) error {
+				calls = append(calls, "second")
+				return nil
+			})
+
+			Expect(provider.ForceFlush(ctx)).To(Succeed())
+			Expect(calls).To(Equal([]string{"first", "second"}))
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+		})
+
+		It("should join hook errors so they can be matched with errors.Is", func() {
+			errHook := errors.New("hook failed")
+			provider.OnFlush(func(context.Context) error { return errHook })
+
+			err := provider.ForceFlush(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, errHook)).To(BeTrue())
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+		})
+	})
+
+	Context("Metric readers", func() {
+		It("should let a manual reader collect metrics on demand", func() {
+			reader := sdkmetric.NewManualReader()
+			p, err := gotel.NewProvider(ctx,
+				gotel.WithDebug(true),
+				gotel.WithMetricReaders(reader),
+				gotel.WithServiceInfo("provider-test", "1.0.0", "test"),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			counter, err := gotel.NewMetricRegistry(p.Meter(), "").Counter("jobs_total", "Total jobs")
+			Expect(err).NotTo(HaveOccurred())
+			counter.Add(ctx, 3)
+
+			var rm metricdata.ResourceMetrics
+			Expect(reader.Collect(ctx, &rm)).To(Succeed())
+			Expect(rm.ScopeMetrics).To(HaveLen(1))
+			Expect(rm.ScopeMetrics[0].Metrics).To(HaveLen(1))
+
+			sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+			Expect(ok).To(BeTrue())
+			Expect(sum.DataPoints[0].Value).To(Equal(int64(3)))
+
+			Expect(p.Shutdown(ctx)).To(Succeed())
+			Expect(reader.Collect(ctx, &rm)).To(MatchError(sdkmetric.ErrReaderShutdown))
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+		})
+	})
+
+	Context("MetricsHandler", func() {
+		It("should respond with 404 when Prometheus is disabled", func() {
+			rec := httptest.NewRecorder()
+			provider.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+		})
+
+		It("should serve registry instruments in the Prometheus format", func() {
+			p, err := gotel.NewProvider(ctx,
+				gotel.WithDebug(true),
+				gotel.WithPrometheus(true),
+				gotel.WithResourceAttr("team", "platform"),
+				gotel.WithServiceInfo("provider-test", "1.0.0", "test"),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			histogram, err := gotel.NewMetricRegistry(p.Meter(), "").Histogram(
+				"job_duration_seconds", "Duration of jobs",
+			)
+			Expect(err).NotTo(HaveOccurred())
+			histogram.Record(ctx, 0.2)
+
+			rec := httptest.NewRecorder()
+			p.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			body := rec.Body.String()
+			Expect(body).To(ContainSubstring("job_duration_seconds_bucket"))
+			Expect(body).To(ContainSubstring("job_duration_seconds_count"))
+			Expect(body).To(MatchRegexp(`target_info\{.*team="platform".*\} 1`))
+
+			Expect(p.Shutdown(ctx)).To(Succeed())
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+		})
+	})
+
+	Context("Shutdown", func() {
+		It("should run shutdown hooks in reverse registration order", func() {
+			var calls []string
+			provider.OnShutdown(func(context.Context) error {
+				calls = append(calls, "first")
+				return nil
+			})
+			provider.OnShutdown(func(context.Context) error {
+				calls = append(calls, "second")
+				return nil
+			})
+
+			Expect(provider.Shutdown(ctx)).To(Succeed())
+			Expect(calls).To(Equal([]string{"second", "first"}))
+		})
+
+		It("should return hook errors that errors.Is can match", func() {
+			hookErr := errors.New("close queue")
+			provider.OnShutdown(func(context.Context) error {
+				return hookErr
+			})
+
+			err := provider.Shutdown(ctx)
+			Expect(errors.Is(err, hookErr)).To(BeTrue())
+		})
+	})
+})
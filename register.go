@@ -0,0 +1,160 @@
+package gotel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterMetrics creates every instrument declared on the struct pointed to
+// by target and assigns it to the corresponding field. Fields are declared with
+// an instrument interface type, or with one of the bindable types such as
+// *Counter or *SyncGauge, and tags:
+//
+//	type Metrics struct {
+//		Requests *gotel.Counter          `metric:"requests_total" description:"Total requests"`
+//		Latency  metric.Float64Histogram `metric:"latency" unit:"s" buckets:"0.1,0.5,1"`
+//	}
+//
+// Fields without a metric tag, or tagged metric:"-", are skipped. Observable
+// instruments are created without callbacks; bind them with
+// MetricRegistry.RegisterCallback. All failures are joined into the returned
+// error, and fields whose instruments could not be created are left unchanged.
+func RegisterMetrics(registry *MetricRegistry, target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("RegisterMetrics requires a non-nil pointer to a struct, got %T", target)
+	}
+
+	var errs []error
+	value = value.Elem()
+
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+
+		name, ok := field.Tag.Lookup("metric")
+		if !ok || name == "-" {
+			continue
+		}
+
+		if !field.IsExported() {
+			errs = append(errs, fmt.Errorf("field %s: metric fields must be exported", field.Name))
+			continue
+		}
+
+		instrument, err := createTaggedInstrument(registry, field, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+			continue
+		}
+
+		value.Field(i).Set(reflect.ValueOf(instrument))
+	}
+
+	return errors.Join(errs...)
+}
+
+// createTaggedInstrument creates the instrument matching the field's type.
+func createTaggedInstrument(registry *MetricRegistry, field reflect.StructField, name string) (any, error) {
+	description := field.Tag.Get("description")
+
+	var unit []metric.InstrumentOption
+	if u, ok := field.Tag.Lookup("unit"); ok {
+		unit = append(unit, metric.WithUnit(u))
+	}
+
+	buckets, err := parseBuckets(field.Tag.Get("buckets"))
+	if err != nil {
+		return nil, err
+	}
+
+	var histogram []metric.HistogramOption
+	if len(buckets) > 0 {
+		histogram = append(histogram, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+
+	isHistogram := field.Type == reflect.TypeFor[metric.Int64Histogram]() ||
+		field.Type == reflect.TypeFor[metric.Float64Histogram]() ||
+		field.Type == reflect.TypeFor[*IntHistogram]() ||
+		field.Type == reflect.TypeFor[*Histogram]()
+	if len(buckets) > 0 && !isHistogram {
+		return nil, fmt.Errorf("buckets are only supported on histograms, not %s", field.Type)
+	}
+
+	switch field.Type {
+	case reflect.TypeFor[metric.Int64Counter](), reflect.TypeFor[*Counter]():
+		return registry.Counter(name, description, convertOptions[metric.Int64CounterOption](unit)...)
+	case reflect.TypeFor[metric.Float64Counter](), reflect.TypeFor[*FloatCounter]():
+		return registry.FloatCounter(name, description, convertOptions[metric.Float64CounterOption](unit)...)
+	case reflect.TypeFor[metric.Int64UpDownCounter](), reflect.TypeFor[*UpDownCounter]():
+		return registry.UpDownCounter(name, description, convertOptions[metric.Int64UpDownCounterOption](unit)...)
+	case reflect.TypeFor[metric.Float64UpDownCounter](), reflect.TypeFor[*FloatUpDownCounter]():
+		return registry.FloatUpDownCounter(name, description, convertOptions[metric.Float64UpDownCounterOption](unit)...)
+	case reflect.TypeFor[metric.Int64Histogram](), reflect.TypeFor[*IntHistogram]():
+		return registry.IntHistogram(name, description, append(
+			convertOptions[metric.Int64HistogramOption](unit), convertOptions[metric.Int64HistogramOption](histogram)...,
+		)...)
+	case reflect.TypeFor[metric.Float64Histogram](), reflect.TypeFor[*Histogram]():
+		return registry.Histogram(name, description, append(
+			convertOptions[metric.Float64HistogramOption](unit), convertOptions[metric.Float64HistogramOption](histogram)...,
+		)...)
+	case reflect.TypeFor[metric.Int64Gauge](), reflect.TypeFor[*SyncGauge]():
+		return registry.SyncGauge(name, description, convertOptions[metric.Int64GaugeOption](unit)...)
+	case reflect.TypeFor[metric.Float64Gauge](), reflect.TypeFor[*FloatSyncGauge]():
+		return registry.FloatSyncGauge(name, description, convertOptions[metric.Float64GaugeOption](unit)...)
+	case reflect.TypeFor[metric.Int64ObservableGauge]():
+		return registry.Gauge(name, description, nil, convertOptions[metric.Int64ObservableGaugeOption](unit)...)
+	case reflect.TypeFor[metric.Float64ObservableGauge]():
+		return registry.FloatGauge(name, description, nil, convertOptions[metric.Float64ObservableGaugeOption](unit)...)
+	case reflect.TypeFor[metric.Int64ObservableCounter]():
+		return registry.ObservableCounter(
+			name, description, nil, convertOptions[metric.Int64ObservableCounterOption](unit)...,
+		)
+	case reflect.TypeFor[metric.Float64ObservableCounter]():
+		return registry.FloatObservableCounter(
+			name, description, nil, convertOptions[metric.Float64ObservableCounterOption](unit)...,
+		)
+	case reflect.TypeFor[metric.Int64ObservableUpDownCounter]():
+		return registry.ObservableUpDownCounter(
+			name, description, nil, convertOptions[metric.Int64ObservableUpDownCounterOption](unit)...,
+		)
+	case reflect.TypeFor[metric.Float64ObservableUpDownCounter]():
+		return registry.FloatObservableUpDownCounter(
+			name, description, nil, convertOptions[metric.Float64ObservableUpDownCounterOption](unit)...,
+		)
+	default:
+		return nil, fmt.Errorf("unsupported instrument type %s", field.Type)
+	}
+}
+
+// parseBuckets parses a comma separated list of bucket boundaries.
+func parseBuckets(tag string) ([]float64, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(tag, ",")
+	buckets := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		bucket, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket boundary %q: %w", part, err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// convertOptions converts generic instrument options into the option type expected
+// by a specific instrument constructor.
+func convertOptions[O any, I any](opts []I) []O {
+	converted := make([]O, 0, len(opts))
+	for _, opt := range opts {
+		converted = append(converted, any(opt).(O))
+	}
+	return converted
+}
@@ -0,0 +1,123 @@
+package gotel_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+type orderMetrics struct {
+	Orders   metric.Int64Counter         `metric:"orders_total" description:"Total orders"`
+	Revenue  metric.Float64Counter       `metric:"revenue_total" description:"Revenue" unit:"USD"`
+	Latency  metric.Float64Histogram     `metric:"checkout_duration" unit:"s" buckets:"0.1, 0.5, 1"`
+	Items    metric.Int64Histogram       `metric:"order_items" buckets:"1,5,10"`
+	Pending  metric.Int64UpDownCounter   `metric:"orders_pending"`
+	Backlog  metric.Int64ObservableGauge `metric:"order_backlog"`
+	Ignored  metric.Int64Counter         `metric:"-"`
+	Untagged metric.Int64Counter
+}
+
+var _ = Describe("RegisterMetrics", func() {
+	var (
+		ctx      context.Context
+		reader   *sdkmetric.ManualReader
+		registry *gotel.MetricRegistry
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+		meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("register-test")
+		registry = gotel.NewMetricRegistry(meter, "shop")
+	})
+
+	It("should create every tagged instrument in one call", func() {
+		var m orderMetrics
+		Expect(gotel.RegisterMetrics(registry, &m)).To(Succeed())
+
+		Expect(m.Orders).NotTo(BeNil())
+		Expect(m.Revenue).NotTo(BeNil())
+		Expect(m.Latency).NotTo(BeNil())
+		Expect(m.Items).NotTo(BeNil())
+		Expect(m.Pending).NotTo(BeNil())
+		Expect(m.Backlog).NotTo(BeNil())
+		Expect(m.Ignored).To(BeNil())
+		Expect(m.Untagged).To(BeNil())
+
+		m.Revenue.Add(ctx, 10)
+		m.Latency.Record(ctx, 0.3)
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["shop_revenue_total"].Unit).To(Equal("USD"))
+		Expect(metrics["shop_revenue_total"].Description).To(Equal("Revenue"))
+		Expect(metrics["shop_checkout_duration"].Unit).To(Equal("s"))
+
+		hist := metrics["shop_checkout_duration"].Data.(metricdata.Histogram[float64])
+		Expect(hist.DataPoints[0].Bounds).To(Equal([]float64{0.1, 0.5, 1}))
+	})
+
+	It("should share instruments with direct registry calls", func() {
+		var m orderMetrics
+		Expect(gotel.RegisterMetrics(registry, &m)).To(Succeed())
+
+		orders, err := registry.Counter("orders_total", "Total orders")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(BeIdenticalTo(m.Orders))
+	})
+
+	It("should accept the bindable instrument types", func() {
+		var m struct {
+			Requests *gotel.Counter            `metric:"requests_total"`
+			Bytes    *gotel.FloatCounter       `metric:"bytes_total"`
+			Inflight *gotel.UpDownCounter      `metric:"inflight"`
+			Queued   *gotel.FloatUpDownCounter `metric:"queued_bytes"`
+			Latency  *gotel.Histogram          `metric:"latency" unit:"s" buckets:"0.1,1"`
+			Items    *gotel.IntHistogram       `metric:"items"`
+			Workers  *gotel.SyncGauge          `metric:"workers"`
+			Load     *gotel.FloatSyncGauge     `metric:"load"`
+		}
+		Expect(gotel.RegisterMetrics(registry, &m)).To(Succeed())
+		Expect(m.Bytes).NotTo(BeNil())
+		Expect(m.Inflight).NotTo(BeNil())
+		Expect(m.Queued).NotTo(BeNil())
+		Expect(m.Items).NotTo(BeNil())
+		Expect(m.Workers).NotTo(BeNil())
+		Expect(m.Load).NotTo(BeNil())
+
+		m.Requests.Bind().Add(ctx, 2)
+		m.Latency.Bind().Record(ctx, 0.5)
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["shop_requests_total"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(Equal(int64(2)))
+		hist := metrics["shop_latency"].Data.(metricdata.Histogram[float64])
+		Expect(hist.DataPoints[0].Bounds).To(Equal([]float64{0.1, 1}))
+	})
+
+	It("should aggregate errors from every invalid field", func() {
+		var m struct {
+			Orders  metric.Int64Counter     `metric:"orders_total" buckets:"1,2"`
+			Latency metric.Float64Histogram `metric:"latency" buckets:"fast"`
+			Name    string                  `metric:"name"`
+			Valid   metric.Int64Counter     `metric:"valid_total"`
+			hidden  metric.Int64Counter     `metric:"hidden_total"`
+		}
+
+		err := gotel.RegisterMetrics(registry, &m)
+		Expect(err).To(MatchError(ContainSubstring("field Orders: buckets are only supported on histograms")))
+		Expect(err).To(MatchError(ContainSubstring(`field Latency: invalid bucket boundary "fast"`)))
+		Expect(err).To(MatchError(ContainSubstring("field Name: unsupported instrument type string")))
+		Expect(err).To(MatchError(ContainSubstring("field hidden: metric fields must be exported")))
+		Expect(m.Valid).NotTo(BeNil())
+		Expect(m.hidden).To(BeNil())
+	})
+
+	It("should reject targets that are not struct pointers", func() {
+		Expect(gotel.RegisterMetrics(registry, orderMetrics{})).To(MatchError(ContainSubstring("pointer to a struct")))
+	})
+})
@@ -0,0 +1,96 @@
+package gotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// Run starts the given HTTP servers and blocks until ctx is cancelled, SIGINT
+// or SIGTERM is received, or one of the servers fails. It then shuts down in
+// the order required to avoid losing telemetry: servers are drained first so
+// in-flight requests can finish their spans, the provider is flushed and
+// finally shut down. All phases share the deadline configured through
+// WithShutdownTimeout.
+func Run(ctx context.Context, provider *Provider, servers ...*http.Server) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := provider.Logger()
+	serveErrs := make(chan error, len(servers))
+
+	for _, server := range servers {
+		go func() {
+			logger.Info(ctx, "Starting HTTP server", zap.String("addr", server.Addr))
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErrs <- fmt.Errorf("http server %s failed: %w", server.Addr, err)
+			}
+		}()
+	}
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+		logger.Info(ctx, "Shutdown signal received")
+	case err := <-serveErrs:
+		logger.Error(ctx, "HTTP server failed, shutting down", zap.Error(err))
+		errs = append(errs, err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(
+		context.WithoutCancel(ctx), provider.config.Shutdown.Timeout,
+	)
+	defer cancel()
+
+	logger.Info(shutdownCtx, "Draining HTTP servers", zap.Int("count", len(servers)))
+	if err := shutdownServers(shutdownCtx, servers); err != nil {
+		logger.Error(shutdownCtx, "Failed to drain HTTP servers", zap.Error(err))
+		errs = append(errs, err)
+	}
+
+	logger.Info(shutdownCtx, "Flushing telemetry")
+	if err := provider.ForceFlush(shutdownCtx); err != nil {
+		logger.Error(shutdownCtx, "Failed to flush telemetry", zap.Error(err))
+		errs = append(errs, err)
+	}
+
+	logger.Info(shutdownCtx, "Shutting down telemetry provider")
+	if err := provider.Shutdown(shutdownCtx); err != nil {
+		logger.Error(shutdownCtx, "Failed to shutdown telemetry provider", zap.Error(err))
+		errs = append(errs, err)
+	}
+
+	_ = logger.Sync()
+	return errors.Join(errs...)
+}
+
+// shutdownServers gracefully shuts down all servers concurrently.
+func shutdownServers(ctx context.Context, servers []*http.Server) error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to shutdown http server %s: %w", server.Addr, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,114 @@
+package gotel_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Run", func() {
+	It("should drain servers and shut down the provider when the context is cancelled", func() {
+		provider, err := gotel.NewProvider(context.Background(),
+			gotel.WithDebug(true),
+			gotel.WithShutdownTimeout(time.Second),
+			gotel.WithServiceInfo("run-test", "1.0.0", "test"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		var shutdown bool
+		provider.OnShutdown(func(context.Context) error {
+			shutdown = true
+			return nil
+		})
+
+		server := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- gotel.Run(ctx, provider, server) }()
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(shutdown).To(BeTrue())
+		Expect(server.ListenAndServe()).To(MatchError(http.ErrServerClosed))
+	})
+
+	It("should drain in-flight requests before shutting down the provider", func() {
+		provider, err := gotel.NewProvider(context.Background(),
+			gotel.WithDebug(true),
+			gotel.WithShutdownTimeout(5*time.Second),
+			gotel.WithServiceInfo("run-test", "1.0.0", "test"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+		record := func(step string) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, step)
+		}
+		provider.OnShutdown(func(context.Context) error {
+			record("shutdown hook")
+			return nil
+		})
+
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(started)
+			<-release
+			record("request")
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addr := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+		server := &http.Server{Addr: addr, Handler: handler}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- gotel.Run(ctx, provider, server) }()
+
+		responses := make(chan int, 1)
+		go func() {
+			defer GinkgoRecover()
+			var resp *http.Response
+			Eventually(func() (err error) {
+				resp, err = http.Get("http://" + addr)
+				return err
+			}).Should(Succeed())
+			responses <- resp.StatusCode
+			_ = resp.Body.Close()
+		}()
+		Eventually(started).Should(BeClosed())
+
+		cancel()
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", addr)
+			if err == nil {
+				_ = conn.Close()
+			}
+			return err
+		}).ShouldNot(Succeed())
+		Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+
+		close(release)
+		Eventually(done).Should(Receive(BeNil()))
+		Eventually(responses).Should(Receive(Equal(http.StatusNoContent)))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(order).To(Equal([]string{"request", "shutdown hook"}))
+	})
+})
@@ -0,0 +1,347 @@
+package gotel
+
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// DefaultRuntimeReadInterval is the default minimum time between two reads of
+// runtime/metrics. It only deduplicates reads by readers collecting together,
+// so it is kept well below export intervals to avoid exporting stale values.
+const DefaultRuntimeReadInterval = time.Second
+
+// runtime/metrics keys read by the runtime instrumentation.
+const (
+	rtMemoryTotal     = "/memory/classes/total:bytes"
+	rtMemoryReleased  = "/memory/classes/heap/released:bytes"
+	rtMemoryStacks    = "/memory/classes/heap/stacks:bytes"
+	rtMemoryOSStacks  = "/memory/classes/os-stacks:bytes"
+	rtMemoryLimit     = "/gc/gomemlimit:bytes"
+	rtHeapAllocBytes  = "/gc/heap/allocs:bytes"
+	rtHeapAllocObject = "/gc/heap/allocs:objects"
+	rtHeapGoal        = "/gc/heap/goal:bytes"
+	rtGoroutines      = "/sched/goroutines:goroutines"
+	rtGOMAXPROCS      = "/sched/gomaxprocs:threads"
+	rtGOGC            = "/gc/gogc:percent"
+	rtSchedLatencies  = "/sched/latencies:seconds"
+	rtGCPauses        = "/sched/pauses/total/gc:seconds"
+)
+
+// Go runtime metric names. The go.* metrics were added to the semantic
+// conventions after v1.26.0, which the rest of the package uses, so they are
+// spelled out here; go.gc.pause.duration is not covered yet and follows their
+// naming.
+const (
+	goMemoryUsedName        = "go.memory.used"
+	goMemoryLimitName       = "go.memory.limit"
+	goMemoryAllocatedName   = "go.memory.allocated"
+	goMemoryAllocationsName = "go.memory.allocations"
+	goMemoryGCGoalName      = "go.memory.gc.goal"
+	goGoroutineCountName    = "go.goroutine.count"
+	goProcessorLimitName    = "go.processor.limit"
+	goConfigGogcName        = "go.config.gogc"
+	goScheduleDurationName  = "go.schedule.duration"
+	goGCPauseDurationName   = "go.gc.pause.duration"
+
+	goMemoryTypeKey = attribute.Key("go.memory.type")
+)
+
+// runtimeReader reads runtime/metrics at most once per interval, so readers
+// collecting at the same time share one read.
+type runtimeReader struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastRead time.Time
+	samples  []metrics.Sample
+	index    map[string]int
+}
+
+func newRuntimeReader(interval time.Duration) *runtimeReader {
+	if interval <= 0 {
+		interval = DefaultRuntimeReadInterval
+	}
+
+	keys := []string{
+		rtMemoryTotal, rtMemoryReleased, rtMemoryStacks, rtMemoryOSStacks, rtMemoryLimit,
+		rtHeapAllocBytes, rtHeapAllocObject, rtHeapGoal, rtGoroutines, rtGOMAXPROCS, rtGOGC,
+	}
+
+	r := &runtimeReader{
+		interval: interval,
+		samples:  make([]metrics.Sample, len(keys)),
+		index:    make(map[string]int, len(keys)),
+	}
+	for i, key := range keys {
+		r.samples[i].Name = key
+		r.index[key] = i
+	}
+	return r
+}
+
+// read refreshes the samples if the interval has passed and calls fn with them
+// while holding the lock, so fn sees a consistent snapshot.
+func (r *runtimeReader) read(fn func(get func(string) metrics.Value)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.lastRead) >= r.interval {
+		metrics.Read(r.samples)
+		r.lastRead = now
+	}
+
+	fn(func(key string) metrics.Value {
+		return r.samples[r.index[key]].Value
+	})
+}
+
+// uint64Value returns the value of an integer sample, or zero when the running
+// Go version does not support it.
+func uint64Value(v metrics.Value) int64 {
+	if v.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(v.Uint64())
+}
+
+// initRuntimeMetrics registers the runtime instruments on meter.
+func (r *runtimeReader) initRuntimeMetrics(meter metric.Meter) error {
+	memoryUsed, err := meter.Int64ObservableUpDownCounter(goMemoryUsedName,
+		metric.WithUnit("By"), metric.WithDescription("Memory used by the Go runtime."))
+	if err != nil {
+		return err
+	}
+	memoryLimit, err := meter.Int64ObservableUpDownCounter(goMemoryLimitName,
+		metric.WithUnit("By"), metric.WithDescription("Go runtime memory limit configured by the user, if a limit exists."))
+	if err != nil {
+		return err
+	}
+	allocated, err := meter.Int64ObservableCounter(goMemoryAllocatedName,
+		metric.WithUnit("By"), metric.WithDescription("Memory allocated to the heap by the application."))
+	if err != nil {
+		return err
+	}
+	allocations, err := meter.Int64ObservableCounter(goMemoryAllocationsName,
+		metric.WithUnit("{allocation}"), metric.WithDescription("Count of allocations to the heap by the application."))
+	if err != nil {
+		return err
+	}
+	gcGoal, err := meter.Int64ObservableUpDownCounter(goMemoryGCGoalName,
+		metric.WithUnit("By"), metric.WithDescription("Heap size target for the end of the GC cycle."))
+	if err != nil {
+		return err
+	}
+	goroutines, err := meter.Int64ObservableUpDownCounter(goGoroutineCountName,
+		metric.WithUnit("{goroutine}"), metric.WithDescription("Count of live goroutines."))
+	if err != nil {
+		return err
+	}
+	processors, err := meter.Int64ObservableUpDownCounter(goProcessorLimitName,
+		metric.WithUnit("{thread}"), metric.WithDescription("The number of OS threads that can execute user-level Go code simultaneously."))
+	if err != nil {
+		return err
+	}
+	gogc, err := meter.Int64ObservableUpDownCounter(goConfigGogcName,
+		metric.WithUnit("%"), metric.WithDescription("Heap size target percentage configured by the user, otherwise 100."))
+	if err != nil {
+		return err
+	}
+
+	stackAttrs := metric.WithAttributes(goMemoryTypeKey.String("stack"))
+	otherAttrs := metric.WithAttributes(goMemoryTypeKey.String("other"))
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		r.read(func(get func(string) metrics.Value) {
+			stacks := uint64Value(get(rtMemoryStacks)) + uint64Value(get(rtMemoryOSStacks))
+			used := uint64Value(get(rtMemoryTotal)) - uint64Value(get(rtMemoryReleased))
+			o.ObserveInt64(memoryUsed, stacks, stackAttrs)
+			o.ObserveInt64(memoryUsed, used-stacks, otherAttrs)
+
+			// The runtime reports math.MaxInt64 when no memory limit is set.
+			if limit := uint64Value(get(rtMemoryLimit)); limit != math.MaxInt64 {
+				o.ObserveInt64(memoryLimit, limit)
+			}
+			// GOGC=off is reported as a negative percentage.
+			if percent := uint64Value(get(rtGOGC)); percent > 0 {
+				o.ObserveInt64(gogc, percent)
+			}
+
+			o.ObserveInt64(allocated, uint64Value(get(rtHeapAllocBytes)))
+			o.ObserveInt64(allocations, uint64Value(get(rtHeapAllocObject)))
+			o.ObserveInt64(gcGoal, uint64Value(get(rtHeapGoal)))
+			o.ObserveInt64(goroutines, uint64Value(get(rtGoroutines)))
+			o.ObserveInt64(processors, uint64Value(get(rtGOMAXPROCS)))
+		})
+		return nil
+	}, memoryUsed, memoryLimit, allocated, allocations, gcGoal, goroutines, processors, gogc)
+	return err
+}
+
+// runtimeScopeName is the instrumentation scope of the runtime histograms.
+const runtimeScopeName = "github.com/iamBelugax/gotel"
+
+// runtimeProducer produces the runtime/metrics histograms in bulk. Observable
+// instruments cannot carry a distribution, and recording every sample into a
+// synchronous histogram costs one call per sample, so the runtime buckets are
+// converted into data points directly, at a cost that only depends on the
+// number of buckets.
+//
+// Producer output bypasses the SDK, so views do not apply to it and it is
+// reported with the temporality chosen when the producer was created.
+type runtimeProducer struct {
+	temporality metricdata.Temporality
+
+	mu         sync.Mutex
+	samples    []metrics.Sample
+	histograms []*runtimeHistogram
+}
+
+// NewRuntimeProducer returns a producer of the go.schedule.duration and
+// go.gc.pause.duration histograms, reported with the temporality selector
+// picks for histograms. WithRuntimeMetrics attaches one to the provider's own
+// exporters; pass one to readers given to WithMetricReaders with
+// sdkmetric.WithProducer to report the histograms there too.
+func NewRuntimeProducer(selector sdkmetric.TemporalitySelector) sdkmetric.Producer {
+	if selector == nil {
+		selector = sdkmetric.DefaultTemporalitySelector
+	}
+	return newRuntimeProducer(selector(sdkmetric.InstrumentKindHistogram))
+}
+
+func newRuntimeProducer(temporality metricdata.Temporality) *runtimeProducer {
+	p := &runtimeProducer{
+		temporality: temporality,
+		samples:     []metrics.Sample{{Name: rtSchedLatencies}, {Name: rtGCPauses}},
+		histograms: []*runtimeHistogram{
+			{name: goScheduleDurationName, description: "The time goroutines have spent in the scheduler in a runnable state before actually running."},
+			{name: goGCPauseDurationName, description: "Distribution of individual GC-related stop-the-world pause latencies."},
+		},
+	}
+
+	// The first read sets the baseline, so the histograms only report samples
+	// taken after the producer was created.
+	now := time.Now()
+	metrics.Read(p.samples)
+	for i, h := range p.histograms {
+		h.reset(p.samples[i].Value, now)
+	}
+	return p
+}
+
+// Produce returns the samples added to the runtime histograms since the
+// producer was created, or since the last call for delta temporality.
+func (p *runtimeProducer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	metrics.Read(p.samples)
+
+	scope := metricdata.ScopeMetrics{Scope: instrumentation.Scope{Name: runtimeScopeName}}
+	for i, h := range p.histograms {
+		if m, ok := h.metric(p.samples[i].Value, p.temporality, now); ok {
+			scope.Metrics = append(scope.Metrics, m)
+		}
+	}
+
+	if len(scope.Metrics) == 0 {
+		return nil, nil
+	}
+	return []metricdata.ScopeMetrics{scope}, nil
+}
+
+// runtimeHistogram converts a runtime/metrics histogram into an OpenTelemetry
+// one with the same bucket boundaries. The runtime only exposes cumulative
+// bucket counts, so the counts of the last reported read are kept as the
+// baseline that is subtracted from the next one.
+type runtimeHistogram struct {
+	name        string
+	description string
+
+	start  time.Time
+	counts []uint64
+}
+
+// reset makes v and now the baseline of the next data point.
+func (h *runtimeHistogram) reset(v metrics.Value, now time.Time) {
+	h.start = now
+	if v.Kind() == metrics.KindFloat64Histogram {
+		h.counts = slices.Clone(v.Float64Histogram().Counts)
+	}
+}
+
+// metric returns the histogram metric for v. It reports false when the running
+// Go version does not support the histogram.
+func (h *runtimeHistogram) metric(
+	v metrics.Value, temporality metricdata.Temporality, now time.Time,
+) (metricdata.Metrics, bool) {
+	if v.Kind() != metrics.KindFloat64Histogram {
+		return metricdata.Metrics{}, false
+	}
+
+	point := runtimeDataPoint(v.Float64Histogram(), h.counts, h.start, now)
+	if temporality == metricdata.DeltaTemporality {
+		h.reset(v, now)
+	}
+
+	return metricdata.Metrics{
+		Name:        h.name,
+		Description: h.description,
+		Unit:        "s",
+		Data: metricdata.Histogram[float64]{
+			DataPoints:  []metricdata.HistogramDataPoint[float64]{point},
+			Temporality: temporality,
+		},
+	}, true
+}
+
+// runtimeDataPoint returns the data point for the samples in sample that are
+// not in baseline. The outer runtime boundaries are infinite and implied by
+// OpenTelemetry's first and last buckets, so the counts map one to one. The
+// runtime does not track the sum, so it is estimated from bucketValue.
+func runtimeDataPoint(
+	sample *metrics.Float64Histogram, baseline []uint64, start, now time.Time,
+) metricdata.HistogramDataPoint[float64] {
+	point := metricdata.HistogramDataPoint[float64]{
+		StartTime:    start,
+		Time:         now,
+		Bounds:       slices.Clone(sample.Buckets[1 : len(sample.Buckets)-1]),
+		BucketCounts: make([]uint64, len(sample.Counts)),
+	}
+
+	for i, count := range sample.Counts {
+		if i < len(baseline) {
+			count -= min(count, baseline[i])
+		}
+		point.BucketCounts[i] = count
+		point.Count += count
+		if count > 0 {
+			point.Sum += float64(count) * bucketValue(sample.Buckets[i], sample.Buckets[i+1])
+		}
+	}
+	return point
+}
+
+// bucketValue returns the value a sample in the runtime bucket [lower, upper)
+// is assumed to have. Samples in the unbounded buckets are assumed to sit on
+// their finite boundary.
+func bucketValue(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	default:
+		return (lower + upper) / 2
+	}
+}
@@ -0,0 +1,144 @@
+package gotel_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Runtime metrics", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should not report runtime metrics unless enabled", func() {
+		reader := sdkmetric.NewManualReader()
+		p, err := gotel.NewProvider(ctx, gotel.WithDebug(true), gotel.WithMetricReaders(reader))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(p.Shutdown, ctx)
+
+		Expect(collectMetrics(ctx, reader)).NotTo(HaveKey("go.goroutine.count"))
+	})
+
+	It("should observe runtime metrics using the semantic convention names", func() {
+		reader := sdkmetric.NewManualReader()
+		p, err := gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithMetricReaders(reader),
+			gotel.WithRuntimeMetrics(0),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(p.Shutdown, ctx)
+
+		metrics := collectMetrics(ctx, reader)
+
+		goroutines := metrics["go.goroutine.count"]
+		Expect(goroutines.Unit).To(Equal("{goroutine}"))
+		Expect(goroutines.Data.(metricdata.Sum[int64]).DataPoints[0].Value).To(BeNumerically(">", 0))
+
+		allocated := metrics["go.memory.allocated"].Data.(metricdata.Sum[int64])
+		Expect(allocated.IsMonotonic).To(BeTrue())
+		Expect(allocated.DataPoints[0].Value).To(BeNumerically(">", 0))
+
+		var types []string
+		for _, point := range metrics["go.memory.used"].Data.(metricdata.Sum[int64]).DataPoints {
+			value, _ := point.Attributes.Value(attribute.Key("go.memory.type"))
+			types = append(types, value.AsString())
+		}
+		Expect(types).To(ConsistOf("stack", "other"))
+
+		Expect(metrics).To(HaveKey("go.processor.limit"))
+		Expect(metrics).To(HaveKey("go.memory.gc.goal"))
+	})
+
+	It("should produce the runtime histograms with the reader's temporality", func() {
+		delta := func(sdkmetric.InstrumentKind) metricdata.Temporality { return metricdata.DeltaTemporality }
+		reader := sdkmetric.NewManualReader(
+			sdkmetric.WithTemporalitySelector(delta),
+			sdkmetric.WithProducer(gotel.NewRuntimeProducer(delta)),
+		)
+		p, err := gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithMetricReaders(reader),
+			gotel.WithRuntimeMetrics(0),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(p.Shutdown, ctx)
+
+		runtime.GC()
+		metrics := collectMetrics(ctx, reader)
+
+		pauses := metrics["go.gc.pause.duration"].Data.(metricdata.Histogram[float64])
+		Expect(pauses.Temporality).To(Equal(metricdata.DeltaTemporality))
+		Expect(pauses.DataPoints[0].Count).To(BeNumerically(">", 0))
+		Expect(pauses.DataPoints[0].BucketCounts).To(HaveLen(len(pauses.DataPoints[0].Bounds) + 1))
+
+		scheduled := metrics["go.schedule.duration"]
+		Expect(scheduled.Unit).To(Equal("s"))
+		Expect(scheduled.Data.(metricdata.Histogram[float64]).Temporality).To(Equal(metricdata.DeltaTemporality))
+
+		runtime.GC()
+		next := collectMetrics(ctx, reader)["go.gc.pause.duration"].Data.(metricdata.Histogram[float64])
+		Expect(next.DataPoints[0].StartTime).To(Equal(pauses.DataPoints[0].Time))
+		Expect(next.DataPoints[0].Count).To(BeNumerically(">", 0))
+	})
+
+	It("should convert runtime histograms without visiting every sample", func() {
+		const samples = 1 << 40
+		buckets := []float64{math.Inf(-1), 0.001, 0.01, math.Inf(1)}
+
+		point := gotel.RuntimeDataPoint(buckets,
+			[]uint64{samples, samples, samples},
+			[]uint64{0, samples / 2, 0},
+		)
+		Expect(point.Bounds).To(Equal([]float64{0.001, 0.01}))
+		Expect(point.BucketCounts).To(Equal([]uint64{samples, samples / 2, samples}))
+		Expect(point.Count).To(Equal(uint64(samples * 5 / 2)))
+		Expect(point.Sum).To(BeNumerically(">", 0))
+	})
+
+	It("should not report the runtime histograms to readers without a runtime producer", func() {
+		reader := sdkmetric.NewManualReader()
+		p, err := gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithMetricReaders(reader),
+			gotel.WithRuntimeMetrics(0),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(p.Shutdown, ctx)
+
+		runtime.GC()
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics).To(HaveKey("go.goroutine.count"))
+		Expect(metrics).NotTo(HaveKey("go.gc.pause.duration"))
+	})
+
+	It("should serve scheduler latency and GC pause histograms", func() {
+		p, err := gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithPrometheus(true),
+			gotel.WithRuntimeMetrics(0),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(p.Shutdown, ctx)
+
+		rec := httptest.NewRecorder()
+		p.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("go_schedule_duration_seconds_bucket"))
+		Expect(rec.Body.String()).To(ContainSubstring("go_gc_pause_duration_seconds_count"))
+		Expect(rec.Body.String()).To(ContainSubstring("go_goroutine_count"))
+	})
+})
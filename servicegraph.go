@@ -0,0 +1,365 @@
+package gotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Defaults for ServiceGraphConfig, matching the collector's servicegraph connector.
+const (
+	DefaultServiceGraphWait     = 2 * time.Second
+	DefaultServiceGraphMaxItems = 1000
+)
+
+// Node names used when one side of an edge is not known.
+const (
+	serviceGraphUserNode    = "user"
+	serviceGraphUnknownNode = "unknown"
+)
+
+// serviceGraphPeerAttributes are the client span attributes naming the server
+// when its own span is not seen, e.g. a database or a third-party API.
+var serviceGraphPeerAttributes = []attribute.Key{
+	semconv.PeerServiceKey,
+	semconv.DBSystemKey,
+	semconv.ServerAddressKey,
+}
+
+// ServiceGraphConfig configures the service graph processor.
+type ServiceGraphConfig struct {
+	Enabled bool
+	// Wait is how long a client or server span waits for its peer before it
+	// is given up on. Expired spans are also checked for every Wait.
+	Wait time.Duration
+	// MaxItems caps the number of unpaired spans kept in memory. When full,
+	// the oldest is given up on.
+	MaxItems int
+	// Buckets are the latency histogram boundaries in seconds.
+	Buckets []float64
+}
+
+// ServiceGraphProcessor is a span processor that builds a who-calls-whom graph
+// from client and server spans, in the same shape as the collector's
+// servicegraph connector.
+//
+// A client span is paired with the server span whose parent it is. Root
+// server spans start the trace, so they are emitted right away with the
+// "user" client. Client spans to services that are not traced in-process,
+// such as databases, are emitted once given up on, with the server named by
+// peer.service, db.system or server.address. Any other span given up on has
+// no edge to emit and is counted in traces_service_graph_unpaired_spans_total
+// instead.
+type ServiceGraphProcessor struct {
+	wait     time.Duration
+	maxItems int
+	now      func() time.Time
+
+	requests      metric.Int64Counter
+	failed        metric.Int64Counter
+	clientLatency metric.Float64Histogram
+	serverLatency metric.Float64Histogram
+	unpaired      metric.Int64Counter
+
+	mu      sync.Mutex
+	pending map[serviceGraphKey]*serviceGraphEdge
+	order   []serviceGraphKey
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+var _ sdktrace.SpanProcessor = (*ServiceGraphProcessor)(nil)
+
+// serviceGraphKey identifies a call: the trace and the client span's ID.
+type serviceGraphKey struct {
+	traceID trace.TraceID
+	spanID  trace.SpanID
+}
+
+// serviceGraphEdge holds the halves of a call seen so far.
+type serviceGraphEdge struct {
+	client, server               string
+	clientSeconds, serverSeconds float64
+	hasClientSpan, hasServerSpan bool
+	failed                       bool
+	expires                      time.Time
+}
+
+// NewServiceGraphProcessor creates the service graph instruments on meter.
+func NewServiceGraphProcessor(meter metric.Meter, cfg ServiceGraphConfig) (*ServiceGraphProcessor, error) {
+	if cfg.Wait <= 0 {
+		cfg.Wait = DefaultServiceGraphWait
+	}
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = DefaultServiceGraphMaxItems
+	}
+	if len(cfg.Buckets) == 0 {
+		cfg.Buckets = DefaultSpanMetricsBuckets
+	}
+
+	p := &ServiceGraphProcessor{
+		wait:     cfg.Wait,
+		maxItems: cfg.MaxItems,
+		now:      time.Now,
+		pending:  make(map[serviceGraphKey]*serviceGraphEdge),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	registry := NewMetricRegistry(meter, "traces_service_graph")
+	var err error
+
+	p.requests, err = registry.Counter("request_total", "Number of requests between two nodes")
+	if err != nil {
+		return nil, err
+	}
+	p.failed, err = registry.Counter("request_failed_total", "Number of failed requests between two nodes")
+	if err != nil {
+		return nil, err
+	}
+	p.clientLatency, err = registry.Histogram("request_client_seconds",
+		"Request duration as seen by the client", metric.WithExplicitBucketBoundaries(cfg.Buckets...))
+	if err != nil {
+		return nil, err
+	}
+	p.serverLatency, err = registry.Histogram("request_server_seconds",
+		"Request duration as seen by the server", metric.WithExplicitBucketBoundaries(cfg.Buckets...))
+	if err != nil {
+		return nil, err
+	}
+	p.unpaired, err = registry.Counter("unpaired_spans_total",
+		"Number of client and server spans given up on without an edge to emit")
+	if err != nil {
+		return nil, err
+	}
+
+	go p.expireLoop()
+	return p, nil
+}
+
+// expireLoop gives up on expired spans every wait, so they are emitted even
+// when no new span ends. It runs until Shutdown.
+func (p *ServiceGraphProcessor) expireLoop() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.wait)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flush(false)
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// OnStart implements sdktrace.SpanProcessor.
+func (p *ServiceGraphProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd stores the span's half of its call, emitting the edge once both halves
+// have been seen.
+func (p *ServiceGraphProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	var key serviceGraphKey
+	switch s.SpanKind() {
+	case trace.SpanKindClient:
+		key = serviceGraphKey{traceID: s.SpanContext().TraceID(), spanID: s.SpanContext().SpanID()}
+	case trace.SpanKindServer:
+		key = serviceGraphKey{traceID: s.SpanContext().TraceID(), spanID: s.Parent().SpanID()}
+	default:
+		return
+	}
+
+	seconds := s.EndTime().Sub(s.StartTime()).Seconds()
+	service := serviceName(s)
+
+	// A server span without a parent starts the trace, so no client will follow.
+	if s.SpanKind() == trace.SpanKindServer && !s.Parent().IsValid() {
+		p.record(&serviceGraphEdge{
+			server:        service,
+			serverSeconds: seconds,
+			hasServerSpan: true,
+			failed:        s.Status().Code == codes.Error,
+		})
+		return
+	}
+
+	var complete []*serviceGraphEdge
+
+	p.mu.Lock()
+	now := p.now()
+	complete = p.expire(now, complete)
+
+	edge, ok := p.pending[key]
+	if !ok {
+		if len(p.pending) >= p.maxItems {
+			complete = p.evictOldest(complete)
+		}
+		edge = &serviceGraphEdge{expires: now.Add(p.wait)}
+		p.pending[key] = edge
+		p.order = append(p.order, key)
+	}
+
+	edge.failed = edge.failed || s.Status().Code == codes.Error
+	if s.SpanKind() == trace.SpanKindClient {
+		edge.client, edge.clientSeconds, edge.hasClientSpan = service, seconds, true
+		if !edge.hasServerSpan {
+			edge.server = peerName(s)
+		}
+	} else {
+		edge.server, edge.serverSeconds, edge.hasServerSpan = service, seconds, true
+	}
+
+	if edge.hasClientSpan && edge.hasServerSpan {
+		delete(p.pending, key)
+		complete = append(complete, edge)
+	}
+	p.mu.Unlock()
+
+	for _, edge := range complete {
+		p.emit(edge)
+	}
+}
+
+// expire removes the edges whose wait has passed. p.mu must be held.
+func (p *ServiceGraphProcessor) expire(now time.Time, expired []*serviceGraphEdge) []*serviceGraphEdge {
+	for len(p.order) > 0 {
+		key := p.order[0]
+		edge, ok := p.pending[key]
+		if ok && now.Before(edge.expires) {
+			break
+		}
+
+		p.order = p.order[1:]
+		if ok {
+			delete(p.pending, key)
+			expired = append(expired, edge)
+		}
+	}
+	return expired
+}
+
+// evictOldest removes the oldest pending edge, skipping keys whose edge was
+// already completed. p.mu must be held.
+func (p *ServiceGraphProcessor) evictOldest(evicted []*serviceGraphEdge) []*serviceGraphEdge {
+	for len(p.order) > 0 {
+		key := p.order[0]
+		p.order = p.order[1:]
+
+		if edge, ok := p.pending[key]; ok {
+			delete(p.pending, key)
+			return append(evicted, edge)
+		}
+	}
+	return evicted
+}
+
+// emit records a paired edge, or an edge to the peer of a client span given
+// up on, and counts any other span given up on as unpaired.
+func (p *ServiceGraphProcessor) emit(edge *serviceGraphEdge) {
+	switch {
+	case edge.hasClientSpan && edge.hasServerSpan:
+		p.record(edge)
+	case edge.hasClientSpan && edge.server != "":
+		p.record(edge)
+	case edge.hasClientSpan:
+		p.unpaired.Add(context.Background(), 1, metric.WithAttributes(attribute.String("client", edge.client)))
+	default:
+		p.unpaired.Add(context.Background(), 1, metric.WithAttributes(attribute.String("server", edge.server)))
+	}
+}
+
+// record emits the metrics of an edge. Only root server spans have no client
+// span, and their client is the user.
+func (p *ServiceGraphProcessor) record(edge *serviceGraphEdge) {
+	client, server := edge.client, edge.server
+	if !edge.hasClientSpan {
+		client = serviceGraphUserNode
+	}
+
+	ctx := context.Background()
+	attrs := metric.WithAttributeSet(attribute.NewSet(
+		attribute.String("client", client),
+		attribute.String("server", server),
+	))
+
+	p.requests.Add(ctx, 1, attrs)
+	if edge.failed {
+		p.failed.Add(ctx, 1, attrs)
+	}
+	if edge.hasClientSpan {
+		p.clientLatency.Record(ctx, edge.clientSeconds, attrs)
+	}
+	if edge.hasServerSpan {
+		p.serverLatency.Record(ctx, edge.serverSeconds, attrs)
+	}
+}
+
+// flush emits pending edges; all of them when force is set, otherwise only
+// the expired ones.
+func (p *ServiceGraphProcessor) flush(force bool) {
+	var edges []*serviceGraphEdge
+
+	p.mu.Lock()
+	if force {
+		for len(p.pending) > 0 {
+			edges = p.evictOldest(edges)
+		}
+		p.order = nil
+	} else {
+		edges = p.expire(p.now(), edges)
+	}
+	p.mu.Unlock()
+
+	for _, edge := range edges {
+		p.emit(edge)
+	}
+}
+
+// Shutdown stops the expiry of pending spans and gives up on all of them.
+func (p *ServiceGraphProcessor) Shutdown(context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.stopped
+	})
+	p.flush(true)
+	return nil
+}
+
+// ForceFlush gives up on the pending spans whose wait has passed.
+func (p *ServiceGraphProcessor) ForceFlush(context.Context) error {
+	p.flush(false)
+	return nil
+}
+
+// serviceName returns the service.name of the span's resource.
+func serviceName(s sdktrace.ReadOnlySpan) string {
+	if s.Resource() != nil {
+		if value, ok := s.Resource().Set().Value(semconv.ServiceNameKey); ok {
+			return value.AsString()
+		}
+	}
+	return serviceGraphUnknownNode
+}
+
+// peerName returns the first of serviceGraphPeerAttributes set on the span.
+func peerName(s sdktrace.ReadOnlySpan) string {
+	for _, key := range serviceGraphPeerAttributes {
+		for _, kv := range s.Attributes() {
+			if kv.Key == key {
+				return kv.Value.Emit()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package gotel_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Service graph", func() {
+	var (
+		ctx       context.Context
+		reader    *sdkmetric.ManualReader
+		processor *gotel.ServiceGraphProcessor
+	)
+
+	// tracerFor returns a tracer whose spans belong to service and feed the processor.
+	tracerFor := func(service string) trace.Tracer {
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+			sdktrace.WithSpanProcessor(processor),
+		)
+		return tp.Tracer("service-graph-test")
+	}
+
+	// requests returns the request counts keyed by "client->server".
+	requests := func() map[string]int64 {
+		metric, ok := collectMetrics(ctx, reader)["traces_service_graph_request_total"]
+		if !ok {
+			return nil
+		}
+
+		counts := map[string]int64{}
+		for _, point := range metric.Data.(metricdata.Sum[int64]).DataPoints {
+			client, _ := point.Attributes.Value("client")
+			server, _ := point.Attributes.Value("server")
+			counts[client.AsString()+"->"+server.AsString()] = point.Value
+		}
+		return counts
+	}
+
+	newProcessor := func(cfg gotel.ServiceGraphConfig) {
+		var err error
+		reader = sdkmetric.NewManualReader()
+		meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("service-graph-test")
+		processor, err = gotel.NewServiceGraphProcessor(meter, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(processor.Shutdown, context.Background())
+	}
+
+	// unpaired returns the unpaired span counts keyed by "client:<name>" or
+	// "server:<name>".
+	unpaired := func() map[string]int64 {
+		metric, ok := collectMetrics(ctx, reader)["traces_service_graph_unpaired_spans_total"]
+		if !ok {
+			return nil
+		}
+
+		counts := map[string]int64{}
+		for _, point := range metric.Data.(metricdata.Sum[int64]).DataPoints {
+			for _, kv := range point.Attributes.ToSlice() {
+				counts[string(kv.Key)+":"+kv.Value.AsString()] = point.Value
+			}
+		}
+		return counts
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		newProcessor(gotel.ServiceGraphConfig{})
+	})
+
+	It("should pair a client span with the server span it parents", func() {
+		clientCtx, client := tracerFor("frontend").Start(ctx, "GET /orders", trace.WithSpanKind(trace.SpanKindClient))
+		_, server := tracerFor("backend").Start(clientCtx, "GET /orders", trace.WithSpanKind(trace.SpanKindServer))
+		server.SetStatus(codes.Error, "boom")
+		server.End()
+		client.End()
+
+		Expect(requests()).To(Equal(map[string]int64{"frontend->backend": 1}))
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["traces_service_graph_request_failed_total"].Data.(metricdata.Sum[int64]).DataPoints[0].Value).
+			To(Equal(int64(1)))
+		Expect(metrics["traces_service_graph_request_client_seconds"].Data.(metricdata.Histogram[float64]).DataPoints).
+			To(HaveLen(1))
+		Expect(metrics["traces_service_graph_request_server_seconds"].Data.(metricdata.Histogram[float64]).DataPoints).
+			To(HaveLen(1))
+	})
+
+	It("should name unpaired client spans after their peer attributes once flushed", func() {
+		_, query := tracerFor("frontend").Start(ctx, "db.query", trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "postgresql")))
+		query.End()
+
+		Expect(requests()).To(BeNil())
+
+		Expect(processor.Shutdown(ctx)).To(Succeed())
+		Expect(requests()).To(Equal(map[string]int64{"frontend->postgresql": 1}))
+	})
+
+	It("should attribute root server spans to the user node", func() {
+		_, server := tracerFor("backend").Start(ctx, "GET /", trace.WithSpanKind(trace.SpanKindServer))
+		server.End()
+
+		Expect(requests()).To(Equal(map[string]int64{"user->backend": 1}))
+	})
+
+	It("should count server spans whose remote caller is not seen instead of inventing a user edge", func() {
+		newProcessor(gotel.ServiceGraphConfig{Wait: time.Millisecond})
+
+		parent := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{1},
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		})
+		_, server := tracerFor("backend").Start(trace.ContextWithRemoteSpanContext(ctx, parent), "GET /orders",
+			trace.WithSpanKind(trace.SpanKindServer))
+		server.End()
+
+		time.Sleep(5 * time.Millisecond)
+		Expect(processor.ForceFlush(ctx)).To(Succeed())
+		Expect(requests()).To(BeNil())
+		Expect(unpaired()).To(Equal(map[string]int64{"server:backend": 1}))
+	})
+
+	It("should count client spans without a peer instead of emitting an unknown server", func() {
+		_, call := tracerFor("frontend").Start(ctx, "call", trace.WithSpanKind(trace.SpanKindClient))
+		call.End()
+
+		Expect(processor.Shutdown(ctx)).To(Succeed())
+		Expect(requests()).To(BeNil())
+		Expect(unpaired()).To(Equal(map[string]int64{"client:frontend": 1}))
+	})
+
+	It("should emit expired edges without waiting for another span or a flush", func() {
+		newProcessor(gotel.ServiceGraphConfig{Wait: 10 * time.Millisecond})
+
+		_, call := tracerFor("frontend").Start(ctx, "call", trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("peer.service", "payments")))
+		call.End()
+
+		Eventually(requests).Should(Equal(map[string]int64{"frontend->payments": 1}))
+	})
+
+	It("should emit edges whose wait has passed on flush", func() {
+		newProcessor(gotel.ServiceGraphConfig{Wait: time.Millisecond})
+
+		_, call := tracerFor("frontend").Start(ctx, "call", trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("peer.service", "payments")))
+		call.End()
+
+		time.Sleep(5 * time.Millisecond)
+		Expect(processor.ForceFlush(ctx)).To(Succeed())
+		Expect(requests()).To(Equal(map[string]int64{"frontend->payments": 1}))
+	})
+
+	It("should bound the pairing state by evicting the oldest span", func() {
+		newProcessor(gotel.ServiceGraphConfig{MaxItems: 2})
+		tracer := tracerFor("frontend")
+
+		for _, peer := range []string{"a", "b", "c"} {
+			_, call := tracer.Start(ctx, "call", trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attribute.String("peer.service", peer)))
+			call.End()
+		}
+
+		Expect(requests()).To(Equal(map[string]int64{"frontend->a": 1}))
+	})
+})
@@ -0,0 +1,146 @@
+package gotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Names of the span metrics, matching the collector's spanmetrics connector.
+const (
+	spanMetricsCallsName    = "traces.span.metrics.calls"
+	spanMetricsDurationName = "traces.span.metrics.duration"
+)
+
+// DefaultSpanMetricsBuckets are the duration histogram boundaries, in seconds,
+// used when SpanMetricsConfig.Buckets is empty.
+var DefaultSpanMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// SpanMetricsConfig configures the span metrics processor.
+type SpanMetricsConfig struct {
+	Enabled bool
+	// Dimensions lists the span attribute keys added to the metrics, on top of
+	// the service name, span name, kind and status code.
+	Dimensions []string
+	// Buckets are the duration histogram boundaries in seconds.
+	Buckets []float64
+	// CardinalityLimit caps the distinct series of each metric. Zero uses
+	// DefaultCardinalityLimit.
+	CardinalityLimit int
+}
+
+// SpanMetricsProcessor is a span processor that derives request rate, error
+// rate and duration metrics from finished spans, in the same shape as the
+// collector's spanmetrics connector.
+//
+// Only recorded spans reach span processors, so with a sampling ratio below
+// one the metrics cover the sampled spans only.
+type SpanMetricsProcessor struct {
+	calls      metric.Int64Counter
+	duration   metric.Float64Histogram
+	dimensions map[attribute.Key]struct{}
+}
+
+var _ sdktrace.SpanProcessor = (*SpanMetricsProcessor)(nil)
+
+// NewSpanMetricsProcessor creates the span metrics instruments on meter.
+func NewSpanMetricsProcessor(meter metric.Meter, cfg SpanMetricsConfig) (*SpanMetricsProcessor, error) {
+	var opts []RegistryOption
+	if cfg.CardinalityLimit > 0 {
+		opts = append(opts, WithCardinalityLimit(cfg.CardinalityLimit))
+	}
+	registry := NewMetricRegistry(meter, "", opts...)
+
+	buckets := cfg.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultSpanMetricsBuckets
+	}
+
+	calls, err := registry.Counter(spanMetricsCallsName, "Number of finished spans", metric.WithUnit("{call}"))
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := registry.Histogram(spanMetricsDurationName, "Duration of finished spans",
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(buckets...))
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := make(map[attribute.Key]struct{}, len(cfg.Dimensions))
+	for _, key := range cfg.Dimensions {
+		dimensions[attribute.Key(key)] = struct{}{}
+	}
+
+	return &SpanMetricsProcessor{calls: calls, duration: duration, dimensions: dimensions}, nil
+}
+
+// OnStart implements sdktrace.SpanProcessor.
+func (p *SpanMetricsProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// OnEnd records the call and its duration. The span's context is attached to
+// the measurement, so duration exemplars link back to the span.
+func (p *SpanMetricsProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	attrs := make([]attribute.KeyValue, 0, 4+len(p.dimensions))
+	attrs = append(attrs,
+		semconv.ServiceNameKey.String(serviceName(s)),
+		attribute.String("span.name", s.Name()),
+		attribute.String("span.kind", spanKindName(s.SpanKind())),
+		attribute.String("status.code", statusCodeName(s.Status().Code)),
+	)
+	if len(p.dimensions) > 0 {
+		for _, kv := range s.Attributes() {
+			if _, ok := p.dimensions[kv.Key]; ok {
+				attrs = append(attrs, kv)
+			}
+		}
+	}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), s.SpanContext())
+	set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	p.calls.Add(ctx, 1, set)
+	p.duration.Record(ctx, s.EndTime().Sub(s.StartTime()).Seconds(), set)
+}
+
+// spanKindName returns the OTLP name of kind, such as SPAN_KIND_SERVER, which
+// the spanmetrics connector uses as the span.kind value.
+func spanKindName(kind trace.SpanKind) string {
+	switch kind {
+	case trace.SpanKindInternal:
+		return "SPAN_KIND_INTERNAL"
+	case trace.SpanKindServer:
+		return "SPAN_KIND_SERVER"
+	case trace.SpanKindClient:
+		return "SPAN_KIND_CLIENT"
+	case trace.SpanKindProducer:
+		return "SPAN_KIND_PRODUCER"
+	case trace.SpanKindConsumer:
+		return "SPAN_KIND_CONSUMER"
+	default:
+		return "SPAN_KIND_UNSPECIFIED"
+	}
+}
+
+// statusCodeName returns the OTLP name of code, such as STATUS_CODE_ERROR,
+// which the spanmetrics connector uses as the status.code value.
+func statusCodeName(code codes.Code) string {
+	switch code {
+	case codes.Ok:
+		return "STATUS_CODE_OK"
+	case codes.Error:
+		return "STATUS_CODE_ERROR"
+	default:
+		return "STATUS_CODE_UNSET"
+	}
+}
+
+// Shutdown implements sdktrace.SpanProcessor.
+func (p *SpanMetricsProcessor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush implements sdktrace.SpanProcessor.
+func (p *SpanMetricsProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,102 @@
+package gotel_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iamBelugax/gotel"
+)
+
+var _ = Describe("Span metrics", func() {
+	var (
+		ctx    context.Context
+		reader *sdkmetric.ManualReader
+	)
+
+	newProvider := func(opts ...gotel.Option) *gotel.Provider {
+		p, err := gotel.NewProvider(ctx, append([]gotel.Option{
+			gotel.WithDebug(true),
+			gotel.WithServiceInfo("spanmetrics-test", "1.0.0", "test"),
+			gotel.WithMetricReaders(reader),
+		}, opts...)...)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(p.Shutdown, ctx)
+		return p
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reader = sdkmetric.NewManualReader()
+	})
+
+	It("should not record span metrics unless enabled", func() {
+		p := newProvider()
+		_, span := p.Tracer().Start(ctx, "work")
+		span.End()
+
+		Expect(collectMetrics(ctx, reader)).NotTo(HaveKey("traces.span.metrics.calls"))
+	})
+
+	It("should count calls and durations by name, kind, status and allowlisted attributes", func() {
+		p := newProvider(gotel.WithSpanMetrics("http.route"))
+
+		for range 2 {
+			_, span := p.Tracer().Start(ctx, "GET /orders", trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attribute.String("http.route", "/orders"), attribute.String("user.id", "42")))
+			span.End()
+		}
+
+		_, failed := p.Tracer().Start(ctx, "charge")
+		failed.SetStatus(codes.Error, "declined")
+		failed.End()
+
+		metrics := collectMetrics(ctx, reader)
+		calls := metrics["traces.span.metrics.calls"].Data.(metricdata.Sum[int64]).DataPoints
+		Expect(calls).To(HaveLen(2))
+
+		byName := map[string]metricdata.DataPoint[int64]{}
+		for _, point := range calls {
+			name, _ := point.Attributes.Value("span.name")
+			byName[name.AsString()] = point
+		}
+
+		orders := byName["GET /orders"]
+		Expect(orders.Value).To(Equal(int64(2)))
+		Expect(orders.Attributes.ToSlice()).To(ConsistOf(
+			attribute.String("service.name", "spanmetrics-test"),
+			attribute.String("span.name", "GET /orders"),
+			attribute.String("span.kind", "SPAN_KIND_SERVER"),
+			attribute.String("status.code", "STATUS_CODE_UNSET"),
+			attribute.String("http.route", "/orders"),
+		))
+
+		charge := byName["charge"]
+		status, _ := charge.Attributes.Value("status.code")
+		Expect(status.AsString()).To(Equal("STATUS_CODE_ERROR"))
+		kind, _ := charge.Attributes.Value("span.kind")
+		Expect(kind.AsString()).To(Equal("SPAN_KIND_INTERNAL"))
+
+		duration := metrics["traces.span.metrics.duration"]
+		Expect(duration.Unit).To(Equal("s"))
+		Expect(duration.Data.(metricdata.Histogram[float64]).DataPoints).To(HaveLen(2))
+	})
+
+	It("should collapse series beyond the cardinality limit", func() {
+		p := newProvider(gotel.WithSpanMetrics(), gotel.WithSpanMetricsCardinalityLimit(2))
+
+		for _, name := range []string{"a", "b", "c", "d"} {
+			_, span := p.Tracer().Start(ctx, name)
+			span.End()
+		}
+
+		calls := collectMetrics(ctx, reader)["traces.span.metrics.calls"].Data.(metricdata.Sum[int64]).DataPoints
+		Expect(calls).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,52 @@
+package gotel
+
+import (
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TemporalityPreference selects the aggregation temporality used by the
+// metric exporter, following the OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE
+// values of the OpenTelemetry specification.
+type TemporalityPreference int
+
+const (
+	// TemporalityCumulative exports every instrument kind as cumulative.
+	TemporalityCumulative TemporalityPreference = iota
+	// TemporalityDelta exports counters, observable counters and histograms as
+	// delta, and up/down counters as cumulative.
+	TemporalityDelta
+	// TemporalityLowMemory exports synchronous counters and histograms as delta,
+	// and everything else as cumulative.
+	TemporalityLowMemory
+)
+
+// temporalitySelector returns the selector for the configured preference,
+// letting per-instrument-kind overrides take precedence.
+func (c *MetricsConfig) temporalitySelector() sdkmetric.TemporalitySelector {
+	preference := c.Temporality
+	overrides := c.TemporalityOverrides
+
+	return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+		if temporality, ok := overrides[kind]; ok {
+			return temporality
+		}
+
+		switch preference {
+		case TemporalityDelta:
+			switch kind {
+			case sdkmetric.InstrumentKindCounter,
+				sdkmetric.InstrumentKindObservableCounter,
+				sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			}
+		case TemporalityLowMemory:
+			switch kind {
+			case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			}
+		}
+
+		return metricdata.CumulativeTemporality
+	}
+}
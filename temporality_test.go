@@ -0,0 +1,86 @@
+package gotel_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+
+	"github.com/iamBelugax/gotel"
+)
+
+const (
+	cumulative = metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	delta      = metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+)
+
+var _ = Describe("Metric temporality", func() {
+	type expectation struct {
+		counter       metricspb.AggregationTemporality
+		upDownCounter metricspb.AggregationTemporality
+		histogram     metricspb.AggregationTemporality
+	}
+
+	DescribeTable("exporting MetricRegistry instruments over OTLP",
+		func(want expectation, opts ...gotel.Option) {
+			ctx := context.Background()
+			collector := &fakeMetricsService{}
+			endpoint := startFakeCollector(func(s *grpc.Server) {
+				collectortrace.RegisterTraceServiceServer(s, fakeTraceService{})
+				collectormetrics.RegisterMetricsServiceServer(s, collector)
+				collectorlogs.RegisterLogsServiceServer(s, fakeLogsService{})
+			})
+
+			opts = append(opts,
+				gotel.WithInsecure(true),
+				gotel.WithEndpoint(endpoint),
+				gotel.WithMetricInterval(time.Hour),
+				gotel.WithServiceInfo("temporality-test", "1.0.0", "test"),
+			)
+			provider, err := gotel.NewProvider(ctx, opts...)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(provider.Shutdown, ctx)
+
+			registry := gotel.NewMetricRegistry(provider.Meter(), "")
+			counter, err := registry.Counter("jobs_total", "Total jobs")
+			Expect(err).NotTo(HaveOccurred())
+			upDownCounter, err := registry.UpDownCounter("jobs_active", "Active jobs")
+			Expect(err).NotTo(HaveOccurred())
+			histogram, err := registry.Histogram("job_duration_seconds", "Job duration")
+			Expect(err).NotTo(HaveOccurred())
+
+			counter.Add(ctx, 1)
+			upDownCounter.Add(ctx, 1)
+			histogram.Record(ctx, 0.5)
+			Expect(provider.ForceFlush(ctx)).To(Succeed())
+
+			Expect(collector.received("jobs_total").GetSum().GetAggregationTemporality()).To(Equal(want.counter))
+			Expect(collector.received("jobs_active").GetSum().GetAggregationTemporality()).To(Equal(want.upDownCounter))
+			Expect(collector.received("job_duration_seconds").GetHistogram().GetAggregationTemporality()).To(Equal(want.histogram))
+		},
+		Entry("cumulative by default",
+			expectation{counter: cumulative, upDownCounter: cumulative, histogram: cumulative},
+		),
+		Entry("delta preference",
+			expectation{counter: delta, upDownCounter: cumulative, histogram: delta},
+			gotel.WithTemporality(gotel.TemporalityDelta),
+		),
+		Entry("low-memory preference",
+			expectation{counter: delta, upDownCounter: cumulative, histogram: delta},
+			gotel.WithTemporality(gotel.TemporalityLowMemory),
+		),
+		Entry("per-instrument-kind override",
+			expectation{counter: delta, upDownCounter: cumulative, histogram: cumulative},
+			gotel.WithTemporality(gotel.TemporalityDelta),
+			gotel.WithTemporalityOverride(sdkmetric.InstrumentKindHistogram, metricdata.CumulativeTemporality),
+		),
+	)
+})
@@ -2,16 +2,21 @@ package gotel
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Span is a wrapper around the OpenTelemetry span.
 type Span struct {
-	sp     trace.Span
-	tracer trace.Tracer
+	sp         trace.Span
+	tracer     trace.Tracer
+	classifier ErrorClassifier
 }
 
 // NewSpan creates and starts a new span using the provided tracer and name.
@@ -28,6 +33,27 @@ func (s *Span) WithAttributes(attrs ...attribute.KeyValue) *Span {
 	return s
 }
 
+// WithAttributesMap adds attributes from a map, converting each value to the
+// attribute type matching its Go type. Values of other types are formatted as
+// strings.
+func (s *Span) WithAttributesMap(attrs map[string]any) *Span {
+	s.sp.SetAttributes(attributesFromMap(attrs)...)
+	return s
+}
+
+// SetName replaces the name the span was started with.
+func (s *Span) SetName(name string) *Span {
+	s.sp.SetName(name)
+	return s
+}
+
+// AddLink links the span to another span, e.g. each of the messages
+// processed by a batch.
+func (s *Span) AddLink(link trace.Link) *Span {
+	s.sp.AddLink(link)
+	return s
+}
+
 // WithStatus sets the status of the span explicitly.
 func (s *Span) WithStatus(code codes.Code, description string) *Span {
 	s.sp.SetStatus(code, description)
@@ -40,10 +66,47 @@ func (s *Span) AddEvent(name string, attrs ...attribute.KeyValue) *Span {
 	return s
 }
 
-// WithError records the given error on the span and sets the span status to error.
+// AddEventAt adds an event that happened at the given time, e.g. when
+// backfilling timings recorded elsewhere.
+func (s *Span) AddEventAt(timestamp time.Time, name string, attrs ...attribute.KeyValue) *Span {
+	s.sp.AddEvent(name, trace.WithTimestamp(timestamp), trace.WithAttributes(attrs...))
+	return s
+}
+
+// WithError records the given error on the span. Attributes of any *Error in
+// the chain are added to the span, and a captured stack trace is added to the
+// exception event. Unless the Tracer's ErrorClassifier treats err as an
+// expected outcome, the span status is set to error and error.type is added.
 func (s *Span) WithError(err error) *Span {
-	if err != nil {
-		s.sp.RecordError(err)
+	if err == nil {
+		return s
+	}
+
+	classify := s.classifier
+	if classify == nil {
+		classify = DefaultErrorClassifier
+	}
+	failed := classify(err)
+
+	details := extractErrorDetails(err)
+	attrs := details.attrs
+	if failed {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(details.errorType))
+	}
+	s.sp.SetAttributes(attrs...)
+
+	// The event is added directly rather than through RecordError, which would
+	// add its own exception.type for the outermost error next to ours.
+	eventAttrs := []attribute.KeyValue{
+		semconv.ExceptionType(details.errorType),
+		semconv.ExceptionMessage(err.Error()),
+	}
+	if details.stack != nil {
+		eventAttrs = append(eventAttrs, semconv.ExceptionStacktrace(string(details.stack)))
+	}
+	s.sp.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(eventAttrs...))
+
+	if failed {
 		s.sp.SetStatus(codes.Error, err.Error())
 	}
 	return s
@@ -64,6 +127,11 @@ func (s *Span) End() {
 	s.sp.End()
 }
 
+// EndAt completes the Span with the given end time.
+func (s *Span) EndAt(timestamp time.Time) {
+	s.sp.End(trace.WithTimestamp(timestamp))
+}
+
 // Context returns the SpanContext of the underlying span.
 func (s *Span) Context() trace.SpanContext {
 	return s.sp.SpanContext()
@@ -74,34 +142,81 @@ func (s *Span) IsRecording() bool {
 	return s.sp.IsRecording()
 }
 
-// ChildSpan starts a new child span from the current one.
+// ChildSpan starts a new child span from the current one. The child is parented
+// to s even if ctx carries another span or none, while ctx's deadline and
+// values are kept.
 func (s *Span) ChildSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, *Span) {
-	ctx, span := s.tracer.Start(ctx, name, opts...)
-	return ctx, &Span{tracer: s.tracer, sp: span}
+	ctx, span := s.tracer.Start(trace.ContextWithSpan(ctx, s.sp), name, opts...)
+	return ctx, &Span{tracer: s.tracer, sp: span, classifier: s.classifier}
+}
+
+// SpanFromContext returns a Span wrapping the span in ctx, such as the server
+// span started by HTTPMiddleware. When ctx has no span, the returned Span wraps
+// a non-recording span, so it is always safe to use. Its child spans are
+// created by the tracer provider of the wrapped span.
+func SpanFromContext(ctx context.Context) *Span {
+	span := trace.SpanFromContext(ctx)
+	return &Span{sp: span, tracer: span.TracerProvider().Tracer(instrumentationName)}
 }
 
 // Tracer is a wrapper around the OpenTelemetry tracer.
 type Tracer struct {
-	tracer trace.Tracer
+	tracer         trace.Tracer
+	classifier     ErrorClassifier
+	panicsAsErrors bool
+	codeAttributes bool
+}
+
+// TracerOption configures a Tracer.
+type TracerOption func(*Tracer)
+
+// WithPanicsAsErrors makes WithSpan recover panics and return them as a
+// *PanicError instead of re-panicking.
+func WithPanicsAsErrors() TracerOption {
+	return func(t *Tracer) {
+		t.panicsAsErrors = true
+	}
 }
 
 // NewTracer creates a new Tracer with an associated service name.
-func NewTracer(tracer trace.Tracer) *Tracer {
-	return &Tracer{tracer: tracer}
+func NewTracer(tracer trace.Tracer, opts ...TracerOption) *Tracer {
+	t := &Tracer{tracer: tracer}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // StartSpan starts a new span with the given name and options.
 func (t *Tracer) StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, *Span) {
-	return NewSpan(ctx, t.tracer, name, opts...)
+	ctx, span := NewSpan(ctx, t.tracer, name, opts...)
+	span.classifier = t.classifier
+	return ctx, span
 }
 
 // WithSpan executes the given function within a new span.
+//
+// If fn panics, the panic is recorded on the span as an exception event with
+// its stack trace and the span status is set to error. The panic is then
+// re-raised, unless the Tracer was created WithPanicsAsErrors, in which case
+// it is returned as a *PanicError.
 func (t *Tracer) WithSpan(
 	ctx context.Context, name string, fn func(context.Context, *Span) error, opts ...trace.SpanStartOption,
-) error {
+) (err error) {
 	ctx, span := t.StartSpan(ctx, name, opts...)
 	defer span.sp.End()
 
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			recordPanic(span.sp, r, stack, !t.panicsAsErrors)
+			if !t.panicsAsErrors {
+				panic(r)
+			}
+			err = &PanicError{Value: r, Stack: stack}
+		}
+	}()
+
 	if err := fn(ctx, span); err != nil {
 		span.WithError(err)
 		return err
@@ -110,3 +225,95 @@ func (t *Tracer) WithSpan(
 	span.WithStatus(codes.Ok, "success")
 	return nil
 }
+
+// Do executes fn within a new span and returns its result, with the same
+// status, error and panic semantics as Tracer.WithSpan.
+func Do[T any](
+	ctx context.Context,
+	tracer *Tracer,
+	name string,
+	fn func(context.Context, *Span) (T, error),
+	opts ...trace.SpanStartOption,
+) (T, error) {
+	var result T
+	err := tracer.WithSpan(ctx, name, func(ctx context.Context, span *Span) error {
+		var err error
+		result, err = fn(ctx, span)
+		return err
+	}, opts...)
+	return result, err
+}
+
+// Do2 is like Do for functions returning two values.
+func Do2[T, U any](
+	ctx context.Context,
+	tracer *Tracer,
+	name string,
+	fn func(context.Context, *Span) (T, U, error),
+	opts ...trace.SpanStartOption,
+) (T, U, error) {
+	var (
+		first  T
+		second U
+	)
+	err := tracer.WithSpan(ctx, name, func(ctx context.Context, span *Span) error {
+		var err error
+		first, second, err = fn(ctx, span)
+		return err
+	}, opts...)
+	return first, second, err
+}
+
+// PanicError is returned by WithSpan for a recovered panic when the Tracer was
+// created WithPanicsAsErrors.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Recover records a panic on span and ends it, then re-panics. It must be
+// deferred directly, so it can recover the panic, and after the span's End so
+// that it runs first. The span's own End still ends it when nothing panics,
+// and is a no-op after a panic:
+//
+//	ctx, span := tracer.Start(ctx, "work")
+//	defer span.End()
+//	defer gotel.Recover(span)
+func Recover(span trace.Span) {
+	if r := recover(); r != nil {
+		recordPanic(span, r, debug.Stack(), true)
+		span.End()
+		panic(r)
+	}
+}
+
+// Recover records a panic on the span and ends it, then re-panics. Like the
+// package-level Recover, it must be deferred directly, after span.End.
+func (s *Span) Recover() {
+	if r := recover(); r != nil {
+		recordPanic(s.sp, r, debug.Stack(), true)
+		s.sp.End()
+		panic(r)
+	}
+}
+
+// recordPanic adds an exception event for a recovered panic and sets the span
+// status to error. escaped reports whether the panic continues past the span.
+func recordPanic(span trace.Span, value any, stack []byte, escaped bool) {
+	message := fmt.Sprint(value)
+	if err, ok := value.(error); ok {
+		message = err.Error()
+	}
+
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionType(fmt.Sprintf("%T", value)),
+		semconv.ExceptionMessage(message),
+		semconv.ExceptionStacktrace(string(stack)),
+		semconv.ExceptionEscaped(escaped),
+	))
+	span.SetStatus(codes.Error, "panic: "+message)
+}
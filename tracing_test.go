@@ -0,0 +1,256 @@
+package gotel_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/iamBelugax/gotel"
+)
+
+// eventAttr returns the value of key on the first event named name.
+func eventAttr(span sdktrace.ReadOnlySpan, name string, key attribute.Key) (attribute.Value, bool) {
+	for _, event := range span.Events() {
+		if event.Name != name {
+			continue
+		}
+		for _, kv := range event.Attributes {
+			if kv.Key == key {
+				return kv.Value, true
+			}
+		}
+	}
+	return attribute.Value{}, false
+}
+
+var _ = Describe("Tracer", func() {
+	var (
+		ctx      context.Context
+		recorder *tracetest.SpanRecorder
+		provider *sdktrace.TracerProvider
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	})
+
+	Context("Span builder", func() {
+		It("should rename spans, add links and convert map attributes by type", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"))
+
+			_, message := provider.Tracer("tracing-test").Start(ctx, "message")
+			message.End()
+
+			_, span := tracer.StartSpan(ctx, "batch")
+			span.SetName("process batch").
+				AddLink(trace.Link{SpanContext: message.SpanContext()}).
+				WithAttributesMap(map[string]any{
+					"batch.size":    12,
+					"batch.retried": true,
+					"batch.ratio":   float32(0.5),
+					"batch.queues":  []string{"a", "b"},
+					"batch.timeout": 2 * time.Second,
+					"batch.huge":    uint64(math.MaxUint64),
+				})
+			span.End()
+
+			ended := recorder.Ended()[1]
+			Expect(ended.Name()).To(Equal("process batch"))
+			Expect(ended.Links()).To(HaveLen(1))
+			Expect(ended.Links()[0].SpanContext.SpanID()).To(Equal(message.SpanContext().SpanID()))
+			Expect(ended.Attributes()).To(ConsistOf(
+				attribute.Int("batch.size", 12),
+				attribute.Bool("batch.retried", true),
+				attribute.Float64("batch.ratio", 0.5),
+				attribute.StringSlice("batch.queues", []string{"a", "b"}),
+				attribute.String("batch.timeout", "2s"),
+				attribute.String("batch.huge", "18446744073709551615"),
+			))
+		})
+
+		It("should backfill event and end timestamps", func() {
+			start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			_, span := gotel.NewTracer(provider.Tracer("tracing-test")).StartSpan(ctx, "import",
+				trace.WithTimestamp(start))
+			span.AddEventAt(start.Add(time.Second), "parsed", attribute.Int("rows", 10))
+			span.EndAt(start.Add(3 * time.Second))
+
+			ended := recorder.Ended()[0]
+			Expect(ended.Events()).To(HaveLen(1))
+			Expect(ended.Events()[0].Time).To(Equal(start.Add(time.Second)))
+			Expect(ended.Events()[0].Attributes).To(ConsistOf(attribute.Int("rows", 10)))
+			Expect(ended.EndTime()).To(Equal(start.Add(3 * time.Second)))
+		})
+	})
+
+	Context("active span", func() {
+		It("should wrap the span already in the context", func() {
+			spanCtx, raw := provider.Tracer("tracing-test").Start(ctx, "request")
+			span := gotel.SpanFromContext(spanCtx)
+			Expect(span.Context()).To(Equal(raw.SpanContext()))
+
+			_, child := span.ChildSpan(spanCtx, "query")
+			child.End()
+			span.WithAttributes(attribute.String("user.id", "42")).End()
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(2))
+			Expect(spans[0].Parent().SpanID()).To(Equal(raw.SpanContext().SpanID()))
+			Expect(spans[1].Attributes()).To(ConsistOf(attribute.String("user.id", "42")))
+		})
+
+		It("should return a usable non-recording span when the context has none", func() {
+			span := gotel.SpanFromContext(ctx)
+			Expect(span.IsRecording()).To(BeFalse())
+			Expect(func() { span.WithAttributes(attribute.Bool("ok", true)).End() }).NotTo(Panic())
+		})
+
+		It("should parent child spans to the receiver even with an unrelated context", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"))
+			_, parent := tracer.StartSpan(ctx, "parent")
+			otherCtx, other := tracer.StartSpan(ctx, "other")
+
+			for _, unrelated := range []context.Context{ctx, otherCtx} {
+				_, child := parent.ChildSpan(unrelated, "child")
+				child.End()
+			}
+			other.End()
+			parent.End()
+
+			for _, child := range recorder.Ended()[:2] {
+				Expect(child.Parent().SpanID()).To(Equal(parent.Context().SpanID()))
+				Expect(child.SpanContext().TraceID()).To(Equal(parent.Context().TraceID()))
+			}
+		})
+	})
+
+	Context("panics in WithSpan", func() {
+		It("should record the panic, end the span and re-panic by default", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"))
+
+			Expect(func() {
+				_ = tracer.WithSpan(ctx, "work", func(context.Context, *gotel.Span) error {
+					panic("boom")
+				})
+			}).To(PanicWith("boom"))
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status().Code).To(Equal(codes.Error))
+			Expect(spans[0].Status().Description).To(Equal("panic: boom"))
+
+			stack, ok := eventAttr(spans[0], "exception", "exception.stacktrace")
+			Expect(ok).To(BeTrue())
+			Expect(stack.AsString()).To(ContainSubstring("tracing_test.go"))
+
+			escaped, _ := eventAttr(spans[0], "exception", "exception.escaped")
+			Expect(escaped.AsBool()).To(BeTrue())
+		})
+
+		It("should return the panic as an error when configured to", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"), gotel.WithPanicsAsErrors())
+
+			err := tracer.WithSpan(ctx, "work", func(context.Context, *gotel.Span) error {
+				panic(errors.New("nil map"))
+			})
+
+			var panicErr *gotel.PanicError
+			Expect(errors.As(err, &panicErr)).To(BeTrue())
+			Expect(panicErr.Error()).To(Equal("panic: nil map"))
+			Expect(panicErr.Stack).NotTo(BeEmpty())
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status().Code).To(Equal(codes.Error))
+
+			exceptionType, _ := eventAttr(spans[0], "exception", "exception.type")
+			Expect(exceptionType.AsString()).To(Equal("*errors.errorString"))
+		})
+	})
+
+	Context("Do", func() {
+		It("should return the result and mark the span successful", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"))
+
+			total, err := gotel.Do(ctx, tracer, "sum", func(_ context.Context, span *gotel.Span) (int, error) {
+				span.WithAttributes(attribute.Int("items", 3))
+				return 42, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(42))
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Name()).To(Equal("sum"))
+			Expect(spans[0].Status().Code).To(Equal(codes.Ok))
+		})
+
+		It("should return both values and record errors", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"))
+			errNotFound := errors.New("not found")
+
+			name, found, err := gotel.Do2(ctx, tracer, "lookup",
+				func(context.Context, *gotel.Span) (string, bool, error) {
+					return "partial", false, errNotFound
+				},
+			)
+			Expect(err).To(MatchError(errNotFound))
+			Expect(name).To(Equal("partial"))
+			Expect(found).To(BeFalse())
+			Expect(recorder.Ended()[0].Status().Code).To(Equal(codes.Error))
+		})
+
+		It("should return the zero value for a panic converted to an error", func() {
+			tracer := gotel.NewTracer(provider.Tracer("tracing-test"), gotel.WithPanicsAsErrors())
+
+			value, err := gotel.Do(ctx, tracer, "work", func(context.Context, *gotel.Span) (string, error) {
+				panic("boom")
+			})
+			Expect(value).To(BeEmpty())
+
+			var panicErr *gotel.PanicError
+			Expect(errors.As(err, &panicErr)).To(BeTrue())
+		})
+	})
+
+	Context("Recover", func() {
+		It("should record and end an arbitrary span before re-panicking", func() {
+			Expect(func() {
+				_, span := provider.Tracer("tracing-test").Start(ctx, "work")
+				defer span.End()
+				defer gotel.Recover(span)
+				panic("boom")
+			}).To(PanicWith("boom"))
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status().Code).To(Equal(codes.Error))
+			_, ok := eventAttr(spans[0], "exception", "exception.stacktrace")
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should leave spans untouched when nothing panics", func() {
+			_, span := gotel.NewTracer(provider.Tracer("tracing-test")).StartSpan(ctx, "work")
+			func() {
+				defer span.Recover()
+			}()
+			span.End()
+
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status().Code).To(Equal(codes.Unset))
+			Expect(spans[0].Events()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package gotel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// MetricView describes how matching instruments are transformed before they
+// are exported. It can be declared in code or loaded from a JSON file with
+// LoadMetricViews.
+type MetricView struct {
+	// Instrument is the instrument name to match. It may contain the wildcards
+	// '*' (any sequence) and '?' (any single character).
+	Instrument string `json:"instrument"`
+
+	// Scope restricts the view to instruments created by the named meter.
+	Scope string `json:"scope,omitempty"`
+
+	// Rename sets a new name for the matched instrument. It cannot be combined
+	// with a wildcard Instrument.
+	Rename string `json:"rename,omitempty"`
+
+	// AttributeKeys, when non-empty, limits recorded attributes to these keys.
+	AttributeKeys []string `json:"attribute_keys,omitempty"`
+
+	// Buckets replaces the explicit histogram bucket boundaries.
+	Buckets []float64 `json:"buckets,omitempty"`
+
+	// Exponential switches the instrument to a base-2 exponential histogram.
+	Exponential *ExponentialHistogram `json:"exponential,omitempty"`
+
+	// Drop discards all measurements of the matched instrument.
+	Drop bool `json:"drop,omitempty"`
+}
+
+// Bounds of ExponentialHistogram.MaxScale accepted by the SDK.
+const (
+	minExponentialScale = -10
+	maxExponentialScale = 20
+)
+
+// ExponentialHistogram configures a base-2 exponential histogram aggregation.
+// MaxSize must be positive and MaxScale between -10 and 20; the SDK defaults
+// are 160 and 20.
+type ExponentialHistogram struct {
+	MaxSize  int32 `json:"max_size"`
+	MaxScale int32 `json:"max_scale"`
+}
+
+// LoadMetricViews reads a JSON array of MetricView definitions from path.
+func LoadMetricViews(path string) ([]MetricView, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metric views file: %w", err)
+	}
+
+	var views []MetricView
+	if err := json.Unmarshal(data, &views); err != nil {
+		return nil, fmt.Errorf("failed to parse metric views file %s: %w", path, err)
+	}
+
+	for _, view := range views {
+		if err := view.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return views, nil
+}
+
+// validate reports configuration errors that the SDK would otherwise only log.
+func (v MetricView) validate() error {
+	if v.Instrument == "" {
+		return errors.New("metric view requires an instrument name")
+	}
+
+	if v.Rename != "" && strings.ContainsAny(v.Instrument, "*?") {
+		return fmt.Errorf("metric view %q cannot rename a wildcard match", v.Instrument)
+	}
+
+	aggregations := 0
+	for _, set := range []bool{v.Drop, len(v.Buckets) > 0, v.Exponential != nil} {
+		if set {
+			aggregations++
+		}
+	}
+	if aggregations > 1 {
+		return fmt.Errorf("metric view %q sets more than one of drop, buckets and exponential", v.Instrument)
+	}
+
+	for i := 1; i < len(v.Buckets); i++ {
+		if v.Buckets[i] <= v.Buckets[i-1] {
+			return fmt.Errorf("metric view %q buckets must be strictly increasing", v.Instrument)
+		}
+	}
+
+	if e := v.Exponential; e != nil {
+		if e.MaxSize <= 0 {
+			return fmt.Errorf("metric view %q exponential max_size must be positive", v.Instrument)
+		}
+		if e.MaxScale < minExponentialScale || e.MaxScale > maxExponentialScale {
+			return fmt.Errorf("metric view %q exponential max_scale must be between %d and %d",
+				v.Instrument, minExponentialScale, maxExponentialScale)
+		}
+	}
+
+	return nil
+}
+
+// sdkView converts the declarative view into an SDK view. A nil selector keeps
+// the SDK default exemplar reservoirs.
+func (v MetricView) sdkView(selector sdkmetric.ExemplarReservoirProviderSelector) sdkmetric.View {
+	criteria := sdkmetric.Instrument{
+		Name:  v.Instrument,
+		Scope: instrumentation.Scope{Name: v.Scope},
+	}
+
+	mask := sdkmetric.Stream{Name: v.Rename, ExemplarReservoirProviderSelector: selector}
+	if len(v.AttributeKeys) > 0 {
+		keys := make([]attribute.Key, len(v.AttributeKeys))
+		for i, key := range v.AttributeKeys {
+			keys[i] = attribute.Key(key)
+		}
+		mask.AttributeFilter = attribute.NewAllowKeysFilter(keys...)
+	}
+
+	switch {
+	case v.Drop:
+		mask.Aggregation = sdkmetric.AggregationDrop{}
+	case len(v.Buckets) > 0:
+		mask.Aggregation = sdkmetric.AggregationExplicitBucketHistogram{Boundaries: v.Buckets}
+	case v.Exponential != nil:
+		mask.Aggregation = sdkmetric.AggregationBase2ExponentialHistogram{
+			MaxSize:  v.Exponential.MaxSize,
+			MaxScale: v.Exponential.MaxScale,
+		}
+	}
+
+	return sdkmetric.NewView(criteria, mask)
+}
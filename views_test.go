@@ -0,0 +1,176 @@
+package gotel_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/iamBelugax/gotel"
+)
+
+// collectMetrics gathers everything the reader has seen, keyed by metric name.
+func collectMetrics(ctx context.Context, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	Expect(reader.Collect(ctx, &rm)).To(Succeed())
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+var _ = Describe("Metric views", func() {
+	var (
+		ctx      context.Context
+		reader   *sdkmetric.ManualReader
+		registry *gotel.MetricRegistry
+	)
+
+	newProvider := func(views ...gotel.MetricView) {
+		reader = sdkmetric.NewManualReader()
+		provider, err := gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithMetricReaders(reader),
+			gotel.WithMetricViews(views...),
+			gotel.WithServiceInfo("views-test", "1.0.0", "test"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(provider.Shutdown, ctx)
+		registry = gotel.NewMetricRegistry(provider.Meter(), "")
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should rename instruments and restrict attribute keys", func() {
+		newProvider(gotel.MetricView{
+			Instrument:    "requests_total",
+			Rename:        "http_requests",
+			AttributeKeys: []string{"method"},
+		})
+
+		counter, err := registry.Counter("requests_total", "Total requests")
+		Expect(err).NotTo(HaveOccurred())
+		counter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("method", "GET"),
+			attribute.String("path", "/users/42"),
+		))
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics).NotTo(HaveKey("requests_total"))
+		Expect(metrics).To(HaveKey("http_requests"))
+
+		sum := metrics["http_requests"].Data.(metricdata.Sum[int64])
+		Expect(sum.DataPoints).To(HaveLen(1))
+		Expect(sum.DataPoints[0].Attributes.Len()).To(Equal(1))
+		Expect(sum.DataPoints[0].Attributes.HasValue("method")).To(BeTrue())
+	})
+
+	It("should re-bucket histograms and drop matching instruments", func() {
+		newProvider(
+			gotel.MetricView{Instrument: "latency_seconds", Buckets: []float64{1, 2}},
+			gotel.MetricView{Instrument: "noisy_*", Drop: true},
+		)
+
+		histogram, err := registry.Histogram("latency_seconds", "Latency")
+		Expect(err).NotTo(HaveOccurred())
+		histogram.Record(ctx, 1.5)
+
+		noisy, err := registry.Counter("noisy_events_total", "Noisy events")
+		Expect(err).NotTo(HaveOccurred())
+		noisy.Add(ctx, 1)
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics).NotTo(HaveKey("noisy_events_total"))
+
+		hist := metrics["latency_seconds"].Data.(metricdata.Histogram[float64])
+		Expect(hist.DataPoints[0].Bounds).To(Equal([]float64{1, 2}))
+		Expect(hist.DataPoints[0].BucketCounts).To(Equal([]uint64{0, 1, 0}))
+	})
+
+	It("should switch histograms to base-2 exponential aggregation", func() {
+		newProvider(gotel.MetricView{
+			Instrument:  "latency_seconds",
+			Exponential: &gotel.ExponentialHistogram{MaxSize: 160, MaxScale: 20},
+		})
+
+		histogram, err := registry.Histogram("latency_seconds", "Latency")
+		Expect(err).NotTo(HaveOccurred())
+		histogram.Record(ctx, 0.3)
+
+		metrics := collectMetrics(ctx, reader)
+		Expect(metrics["latency_seconds"].Data).To(BeAssignableToTypeOf(metricdata.ExponentialHistogram[float64]{}))
+	})
+
+	It("should reject renaming a wildcard match before installing any provider", func() {
+		tracerProvider := otel.GetTracerProvider()
+
+		_, err := gotel.NewProvider(ctx,
+			gotel.WithDebug(true),
+			gotel.WithMetricViews(gotel.MetricView{Instrument: "http_*", Rename: "requests"}),
+		)
+		Expect(err).To(MatchError(ContainSubstring("cannot rename a wildcard match")))
+		Expect(otel.GetTracerProvider()).To(BeIdenticalTo(tracerProvider))
+	})
+
+	DescribeTable("should reject views the SDK would silently ignore",
+		func(view gotel.MetricView, message string) {
+			_, err := gotel.NewProvider(ctx, gotel.WithDebug(true), gotel.WithMetricViews(view))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("missing instrument", gotel.MetricView{}, "requires an instrument name"),
+		Entry("several aggregations",
+			gotel.MetricView{Instrument: "latency", Drop: true, Buckets: []float64{1}},
+			"more than one of drop, buckets and exponential"),
+		Entry("decreasing buckets",
+			gotel.MetricView{Instrument: "latency", Buckets: []float64{1, 0.5}},
+			"strictly increasing"),
+		Entry("repeated buckets",
+			gotel.MetricView{Instrument: "latency", Buckets: []float64{0.5, 0.5}},
+			"strictly increasing"),
+		Entry("zero exponential size",
+			gotel.MetricView{Instrument: "latency", Exponential: &gotel.ExponentialHistogram{MaxScale: 20}},
+			"max_size must be positive"),
+		Entry("exponential scale above 20",
+			gotel.MetricView{Instrument: "latency", Exponential: &gotel.ExponentialHistogram{MaxSize: 160, MaxScale: 21}},
+			"max_scale must be between -10 and 20"),
+		Entry("exponential scale below -10",
+			gotel.MetricView{Instrument: "latency", Exponential: &gotel.ExponentialHistogram{MaxSize: 160, MaxScale: -11}},
+			"max_scale must be between -10 and 20"),
+	)
+
+	It("should reject an empty exponential histogram loaded from JSON", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "views.json")
+		Expect(os.WriteFile(path, []byte(`[{"instrument": "latency_seconds", "exponential": {}}]`), 0o600)).To(Succeed())
+
+		_, err := gotel.LoadMetricViews(path)
+		Expect(err).To(MatchError(ContainSubstring("max_size must be positive")))
+	})
+
+	It("should load views from a JSON file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "views.json")
+		Expect(os.WriteFile(path, []byte(`[
+			{"instrument": "latency_seconds", "scope": "svc", "buckets": [0.1, 1]},
+			{"instrument": "debug_*", "drop": true}
+		]`), 0o600)).To(Succeed())
+
+		views, err := gotel.LoadMetricViews(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(views).To(Equal([]gotel.MetricView{
+			{Instrument: "latency_seconds", Scope: "svc", Buckets: []float64{0.1, 1}},
+			{Instrument: "debug_*", Drop: true},
+		}))
+	})
+})